@@ -3,8 +3,11 @@ package seaweedfs
 import (
 	"bytes"
 	"context"
+	"crypto/md5" //nolint:gosec
 	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -13,6 +16,7 @@ import (
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -23,12 +27,35 @@ import (
 	"github.com/aws/smithy-go"
 )
 
+const (
+	deleteObjectsBatchSize   = 1000
+	deleteObjectsConcurrency = 4
+
+	// Objects larger than one part are uploaded with multipart upload.
+	s3ObjectPartSize = 16 << 20
+
+	// IAM keeps at most this many versions of a managed policy.
+	maxPolicyVersions = 5
+)
+
 type iamClientConfig struct {
 	Endpoint  string
 	Region    string
 	AccessKey string
 	SecretKey string
 	Insecure  bool
+
+	CACertPEM     string
+	ClientCertPEM string
+	ClientKeyPEM  string
+
+	AssumeRole *assumeRoleConfig
+}
+
+type assumeRoleConfig struct {
+	RoleARN     string
+	SessionName string
+	Duration    time.Duration
 }
 
 type iamClient struct {
@@ -83,6 +110,42 @@ type iamAccessKeyMetadata struct {
 	Status      string `xml:"Status"`
 }
 
+type bucketLifecycleRule struct {
+	ID                                 string
+	Status                             string
+	Prefix                             string
+	Tags                               map[string]string
+	ExpirationDays                     int32
+	NoncurrentVersionExpirationDays    int32
+	AbortIncompleteMultipartUploadDays int32
+}
+
+type bucketCORSRule struct {
+	ID             string
+	AllowedHeaders []string
+	AllowedMethods []string
+	AllowedOrigins []string
+	ExposeHeaders  []string
+	MaxAgeSeconds  int32
+}
+
+type s3ObjectInput struct {
+	Bucket       string
+	Key          string
+	ContentType  string
+	CacheControl string
+	Metadata     map[string]string
+}
+
+type s3ObjectInfo struct {
+	ETag         string
+	VersionID    string
+	ContentType  string
+	CacheControl string
+	Metadata     map[string]string
+	Size         int64
+}
+
 type s3Tagging struct {
 	XMLName xml.Name `xml:"Tagging"`
 	TagSet  []s3Tag  `xml:"TagSet>Tag"`
@@ -115,6 +178,143 @@ type iamUser struct {
 	Path     string `xml:"Path"`
 }
 
+type listUsersResponse struct {
+	Users       []iamUser `xml:"ListUsersResult>Users>member"`
+	IsTruncated bool      `xml:"ListUsersResult>IsTruncated"`
+	Marker      string    `xml:"ListUsersResult>Marker"`
+}
+
+type listUserPoliciesResponse struct {
+	PolicyNames []string `xml:"ListUserPoliciesResult>PolicyNames>member"`
+	IsTruncated bool     `xml:"ListUserPoliciesResult>IsTruncated"`
+	Marker      string   `xml:"ListUserPoliciesResult>Marker"`
+}
+
+type listUserTagsResponse struct {
+	Tags        []iamTag `xml:"ListUserTagsResult>Tags>member"`
+	IsTruncated bool     `xml:"ListUserTagsResult>IsTruncated"`
+	Marker      string   `xml:"ListUserTagsResult>Marker"`
+}
+
+type iamTag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+type createGroupResponse struct {
+	Group iamGroup `xml:"CreateGroupResult>Group"`
+}
+
+type getGroupResponse struct {
+	Group       iamGroup  `xml:"GetGroupResult>Group"`
+	Users       []iamUser `xml:"GetGroupResult>Users>member"`
+	IsTruncated bool      `xml:"GetGroupResult>IsTruncated"`
+	Marker      string    `xml:"GetGroupResult>Marker"`
+}
+
+type getGroupPolicyResponse struct {
+	GroupName      string `xml:"GetGroupPolicyResult>GroupName"`
+	PolicyName     string `xml:"GetGroupPolicyResult>PolicyName"`
+	PolicyDocument string `xml:"GetGroupPolicyResult>PolicyDocument"`
+}
+
+type createPolicyResponse struct {
+	Policy iamPolicy `xml:"CreatePolicyResult>Policy"`
+}
+
+type getPolicyResponse struct {
+	Policy iamPolicy `xml:"GetPolicyResult>Policy"`
+}
+
+type listPoliciesResponse struct {
+	Policies    []iamPolicy `xml:"ListPoliciesResult>Policies>member"`
+	IsTruncated bool        `xml:"ListPoliciesResult>IsTruncated"`
+	Marker      string      `xml:"ListPoliciesResult>Marker"`
+}
+
+type getPolicyVersionResponse struct {
+	PolicyVersion iamPolicyVersion `xml:"GetPolicyVersionResult>PolicyVersion"`
+}
+
+type createPolicyVersionResponse struct {
+	PolicyVersion iamPolicyVersion `xml:"CreatePolicyVersionResult>PolicyVersion"`
+}
+
+type listPolicyVersionsResponse struct {
+	Versions    []iamPolicyVersion `xml:"ListPolicyVersionsResult>Versions>member"`
+	IsTruncated bool               `xml:"ListPolicyVersionsResult>IsTruncated"`
+	Marker      string             `xml:"ListPolicyVersionsResult>Marker"`
+}
+
+type listAttachedUserPoliciesResponse struct {
+	AttachedPolicies []iamAttachedPolicy `xml:"ListAttachedUserPoliciesResult>AttachedPolicies>member"`
+	IsTruncated      bool                `xml:"ListAttachedUserPoliciesResult>IsTruncated"`
+	Marker           string              `xml:"ListAttachedUserPoliciesResult>Marker"`
+}
+
+type iamPolicy struct {
+	PolicyName       string `xml:"PolicyName"`
+	PolicyID         string `xml:"PolicyId"`
+	Arn              string `xml:"Arn"`
+	Path             string `xml:"Path"`
+	Description      string `xml:"Description"`
+	DefaultVersionID string `xml:"DefaultVersionId"`
+	AttachmentCount  int    `xml:"AttachmentCount"`
+}
+
+type iamPolicyVersion struct {
+	VersionID        string `xml:"VersionId"`
+	Document         string `xml:"Document"`
+	IsDefaultVersion bool   `xml:"IsDefaultVersion"`
+	CreateDate       string `xml:"CreateDate"`
+}
+
+type iamAttachedPolicy struct {
+	PolicyName string `xml:"PolicyName"`
+	PolicyArn  string `xml:"PolicyArn"`
+}
+
+type createRoleResponse struct {
+	Role iamRole `xml:"CreateRoleResult>Role"`
+}
+
+type getRoleResponse struct {
+	Role iamRole `xml:"GetRoleResult>Role"`
+}
+
+type getRolePolicyResponse struct {
+	RoleName       string `xml:"GetRolePolicyResult>RoleName"`
+	PolicyName     string `xml:"GetRolePolicyResult>PolicyName"`
+	PolicyDocument string `xml:"GetRolePolicyResult>PolicyDocument"`
+}
+
+type iamRole struct {
+	RoleName                 string `xml:"RoleName"`
+	RoleID                   string `xml:"RoleId"`
+	Arn                      string `xml:"Arn"`
+	Path                     string `xml:"Path"`
+	Description              string `xml:"Description"`
+	AssumeRolePolicyDocument string `xml:"AssumeRolePolicyDocument"`
+}
+
+type assumeRoleResponse struct {
+	Credentials stsCredentials `xml:"AssumeRoleResult>Credentials"`
+}
+
+type stsCredentials struct {
+	AccessKeyID     string `xml:"AccessKeyId"`
+	SecretAccessKey string `xml:"SecretAccessKey"`
+	SessionToken    string `xml:"SessionToken"`
+	Expiration      string `xml:"Expiration"`
+}
+
+type iamGroup struct {
+	GroupName string `xml:"GroupName"`
+	Arn       string `xml:"Arn"`
+	GroupID   string `xml:"GroupId"`
+	Path      string `xml:"Path"`
+}
+
 func newIAMClient(cfg iamClientConfig) (*iamClient, error) {
 	if cfg.Endpoint == "" {
 		return nil, errors.New("endpoint is required")
@@ -126,10 +326,11 @@ func newIAMClient(cfg iamClientConfig) (*iamClient, error) {
 		return nil, errors.New("access_key and secret_key are required")
 	}
 
-	tr := &http.Transport{}
-	if cfg.Insecure {
-		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
 	}
+	tr := &http.Transport{TLSClientConfig: tlsConfig}
 
 	client := &iamClient{
 		endpoint: strings.TrimRight(cfg.Endpoint, "/"),
@@ -148,6 +349,18 @@ func newIAMClient(cfg iamClientConfig) (*iamClient, error) {
 		},
 	}
 
+	if cfg.AssumeRole != nil {
+		if cfg.AssumeRole.RoleARN == "" {
+			return nil, errors.New("assume_role: role_arn is required")
+		}
+		// STS calls themselves are signed with the static key pair.
+		sts := *client
+		client.creds = aws.NewCredentialsCache(&assumeRoleCredentialsProvider{
+			sts: &sts,
+			cfg: *cfg.AssumeRole,
+		})
+	}
+
 	client.s3 = s3.New(s3.Options{
 		Region:       client.region,
 		Credentials:  client.creds,
@@ -159,6 +372,42 @@ func newIAMClient(cfg iamClientConfig) (*iamClient, error) {
 	return client, nil
 }
 
+// newTLSConfig returns nil when the default transport settings apply.
+func newTLSConfig(cfg iamClientConfig) (*tls.Config, error) {
+	if !cfg.Insecure && cfg.CACertPEM == "" && cfg.ClientCertPEM == "" && cfg.ClientKeyPEM == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.Insecure, //nolint:gosec
+	}
+
+	if cfg.CACertPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(cfg.CACertPEM)) {
+			return nil, errors.New("ca certificate: no valid PEM certificates found")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (cfg.ClientCertPEM == "") != (cfg.ClientKeyPEM == "") {
+		return nil, errors.New("client_cert and client_key must be set together")
+	}
+	if cfg.ClientCertPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(cfg.ClientCertPEM), []byte(cfg.ClientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
 func (c *iamClient) CreateUser(ctx context.Context, userName string, path string) (getUserResponse, error) {
 	vals := url.Values{}
 	vals.Set("Action", "CreateUser")
@@ -198,6 +447,68 @@ func (c *iamClient) DeleteUser(ctx context.Context, userName string) error {
 	return c.doIAMAction(ctx, vals, nil)
 }
 
+// ListUsers returns all users whose path starts with pathPrefix, following
+// Marker pagination. The prefix is also applied locally because SeaweedFS
+// may ignore it; users without a path are treated as having path "/".
+func (c *iamClient) ListUsers(ctx context.Context, pathPrefix string) ([]iamUser, error) {
+	var users []iamUser
+	marker := ""
+	for {
+		vals := url.Values{}
+		vals.Set("Action", "ListUsers")
+		vals.Set("Version", "2010-05-08")
+		if pathPrefix != "" {
+			vals.Set("PathPrefix", pathPrefix)
+		}
+		if marker != "" {
+			vals.Set("Marker", marker)
+		}
+
+		var out listUsersResponse
+		if err := c.doIAMAction(ctx, vals, &out); err != nil {
+			return nil, err
+		}
+		for _, user := range out.Users {
+			if user.Path == "" {
+				user.Path = "/"
+			}
+			if strings.HasPrefix(user.Path, pathPrefix) {
+				users = append(users, user)
+			}
+		}
+		if !out.IsTruncated || out.Marker == "" {
+			return users, nil
+		}
+		marker = out.Marker
+	}
+}
+
+func (c *iamClient) ListUserTags(ctx context.Context, userName string) (map[string]string, error) {
+	tags := map[string]string{}
+	marker := ""
+	for {
+		vals := url.Values{}
+		vals.Set("Action", "ListUserTags")
+		vals.Set("Version", "2010-05-08")
+		vals.Set("UserName", userName)
+		if marker != "" {
+			vals.Set("Marker", marker)
+		}
+
+		var out listUserTagsResponse
+		if err := c.doIAMAction(ctx, vals, &out); err != nil {
+			return nil, err
+		}
+		for _, tag := range out.Tags {
+			tags[tag.Key] = tag.Value
+		}
+		if !out.IsTruncated || out.Marker == "" {
+			return tags, nil
+		}
+		marker = out.Marker
+	}
+}
+
 func (c *iamClient) CreateAccessKey(ctx context.Context, userName string) (iamAccessKey, error) {
 	vals := url.Values{}
 	vals.Set("Action", "CreateAccessKey")
@@ -225,6 +536,17 @@ func (c *iamClient) ListAccessKeys(ctx context.Context, userName string) ([]iamA
 	return out.Items, nil
 }
 
+func (c *iamClient) UpdateAccessKey(ctx context.Context, userName string, accessKeyID string, status string) error {
+	vals := url.Values{}
+	vals.Set("Action", "UpdateAccessKey")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("UserName", userName)
+	vals.Set("AccessKeyId", accessKeyID)
+	vals.Set("Status", status)
+
+	return c.doIAMAction(ctx, vals, nil)
+}
+
 func (c *iamClient) DeleteAccessKey(ctx context.Context, userName string, accessKeyID string) error {
 	vals := url.Values{}
 	vals.Set("Action", "DeleteAccessKey")
@@ -265,6 +587,31 @@ func (c *iamClient) GetUserPolicy(ctx context.Context, userName string, policyNa
 	return decoded, nil
 }
 
+// ListUserPolicies returns the names of the inline policies of the user.
+func (c *iamClient) ListUserPolicies(ctx context.Context, userName string) ([]string, error) {
+	var names []string
+	marker := ""
+	for {
+		vals := url.Values{}
+		vals.Set("Action", "ListUserPolicies")
+		vals.Set("Version", "2010-05-08")
+		vals.Set("UserName", userName)
+		if marker != "" {
+			vals.Set("Marker", marker)
+		}
+
+		var out listUserPoliciesResponse
+		if err := c.doIAMAction(ctx, vals, &out); err != nil {
+			return nil, err
+		}
+		names = append(names, out.PolicyNames...)
+		if !out.IsTruncated || out.Marker == "" {
+			return names, nil
+		}
+		marker = out.Marker
+	}
+}
+
 func (c *iamClient) DeleteUserPolicy(ctx context.Context, userName string, policyName string) error {
 	vals := url.Values{}
 	vals.Set("Action", "DeleteUserPolicy")
@@ -275,121 +622,1263 @@ func (c *iamClient) DeleteUserPolicy(ctx context.Context, userName string, polic
 	return c.doIAMAction(ctx, vals, nil)
 }
 
-func (c *iamClient) CreateBucket(ctx context.Context, name string) error {
-	path := "/" + name
-	_, err := c.doSignedRequest(ctx, "s3", http.MethodPut, c.endpoint+path, "", "", nil)
-	return err
-}
-
-func (c *iamClient) HeadBucket(ctx context.Context, name string) error {
-	path := "/" + name
-	_, err := c.doSignedRequest(ctx, "s3", http.MethodHead, c.endpoint+path, "", "", nil)
-	return err
-}
+func (c *iamClient) CreateGroup(ctx context.Context, groupName string, path string) (iamGroup, error) {
+	vals := url.Values{}
+	vals.Set("Action", "CreateGroup")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("GroupName", groupName)
+	if path != "" {
+		vals.Set("Path", path)
+	}
 
-func (c *iamClient) DeleteBucket(ctx context.Context, name string) error {
-	path := "/" + name
-	_, err := c.doSignedRequest(ctx, "s3", http.MethodDelete, c.endpoint+path, "", "", nil)
-	return err
+	var out createGroupResponse
+	if err := c.doIAMAction(ctx, vals, &out); err != nil {
+		return iamGroup{}, err
+	}
+	return out.Group, nil
 }
 
-func (c *iamClient) GetBucketTags(ctx context.Context, name string) (map[string]string, error) {
-	out, err := c.s3.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
-		Bucket: aws.String(name),
-	})
-	if err != nil {
-		if isNoSuchTagSetError(err) {
-			return map[string]string{}, nil
+// GetGroup returns the group and all of its members, following pagination.
+func (c *iamClient) GetGroup(ctx context.Context, groupName string) (iamGroup, []iamUser, error) {
+	var users []iamUser
+	marker := ""
+	for {
+		vals := url.Values{}
+		vals.Set("Action", "GetGroup")
+		vals.Set("Version", "2010-05-08")
+		vals.Set("GroupName", groupName)
+		if marker != "" {
+			vals.Set("Marker", marker)
 		}
-		return nil, fmt.Errorf("get bucket tagging: %w", err)
-	}
 
-	tags := make(map[string]string, len(out.TagSet))
-	for _, tag := range out.TagSet {
-		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		var out getGroupResponse
+		if err := c.doIAMAction(ctx, vals, &out); err != nil {
+			return iamGroup{}, nil, err
+		}
+		users = append(users, out.Users...)
+		if !out.IsTruncated || out.Marker == "" {
+			return out.Group, users, nil
+		}
+		marker = out.Marker
 	}
-	return tags, nil
 }
 
-func (c *iamClient) PutBucketTags(ctx context.Context, name string, tags map[string]string) error {
-	keys := make([]string, 0, len(tags))
-	for k := range tags {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
+func (c *iamClient) DeleteGroup(ctx context.Context, groupName string) error {
+	vals := url.Values{}
+	vals.Set("Action", "DeleteGroup")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("GroupName", groupName)
 
-	tagSet := make([]s3types.Tag, 0, len(tags))
-	for _, key := range keys {
-		value := tags[key]
-		tagSet = append(tagSet, s3types.Tag{
-			Key:   aws.String(key),
-			Value: aws.String(value),
-		})
-	}
+	return c.doIAMAction(ctx, vals, nil)
+}
 
-	_, err := c.s3.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
-		Bucket: aws.String(name),
-		Tagging: &s3types.Tagging{
-			TagSet: tagSet,
-		},
-	})
-	if err != nil {
-		return fmt.Errorf("put bucket tagging: %w", err)
-	}
-	return nil
+func (c *iamClient) AddUserToGroup(ctx context.Context, groupName string, userName string) error {
+	vals := url.Values{}
+	vals.Set("Action", "AddUserToGroup")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("GroupName", groupName)
+	vals.Set("UserName", userName)
+
+	return c.doIAMAction(ctx, vals, nil)
 }
 
-func (c *iamClient) DeleteBucketTags(ctx context.Context, name string) error {
-	_, err := c.s3.DeleteBucketTagging(ctx, &s3.DeleteBucketTaggingInput{
-		Bucket: aws.String(name),
-	})
-	if err != nil {
-		return fmt.Errorf("delete bucket tagging: %w", err)
-	}
-	return nil
+func (c *iamClient) RemoveUserFromGroup(ctx context.Context, groupName string, userName string) error {
+	vals := url.Values{}
+	vals.Set("Action", "RemoveUserFromGroup")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("GroupName", groupName)
+	vals.Set("UserName", userName)
+
+	return c.doIAMAction(ctx, vals, nil)
 }
 
-func (c *iamClient) doIAMAction(ctx context.Context, form url.Values, out any) error {
-	body := form.Encode()
-	_, err := c.doSignedRequest(
-		ctx,
-		"iam",
-		http.MethodPost,
-		c.endpoint+"/",
-		"application/x-www-form-urlencoded",
-		body,
-		out,
-	)
-	return err
+func (c *iamClient) PutGroupPolicy(ctx context.Context, groupName string, policyName string, policyDocument string) error {
+	vals := url.Values{}
+	vals.Set("Action", "PutGroupPolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("GroupName", groupName)
+	vals.Set("PolicyName", policyName)
+	vals.Set("PolicyDocument", policyDocument)
+
+	return c.doIAMAction(ctx, vals, nil)
 }
 
-func (c *iamClient) doSignedRequest(
-	ctx context.Context,
-	service string,
-	method string,
-	requestURL string,
-	contentType string,
-	body string,
-	out any,
-) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewBufferString(body))
-	if err != nil {
-		return nil, err
-	}
+func (c *iamClient) GetGroupPolicy(ctx context.Context, groupName string, policyName string) (string, error) {
+	vals := url.Values{}
+	vals.Set("Action", "GetGroupPolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("GroupName", groupName)
+	vals.Set("PolicyName", policyName)
 
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
+	var out getGroupPolicyResponse
+	if err := c.doIAMAction(ctx, vals, &out); err != nil {
+		return "", err
 	}
-	req.Header.Set("Host", req.URL.Host)
 
-	creds, err := c.creds.Retrieve(ctx)
+	decoded, err := url.QueryUnescape(out.PolicyDocument)
 	if err != nil {
-		return nil, fmt.Errorf("retrieve credentials: %w", err)
+		return out.PolicyDocument, nil
 	}
+	return decoded, nil
+}
 
-	sum := sha256.Sum256([]byte(body))
-	hash := fmt.Sprintf("%x", sum)
-	ctx = v4.SetPayloadHash(ctx, hash)
+func (c *iamClient) DeleteGroupPolicy(ctx context.Context, groupName string, policyName string) error {
+	vals := url.Values{}
+	vals.Set("Action", "DeleteGroupPolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("GroupName", groupName)
+	vals.Set("PolicyName", policyName)
+
+	return c.doIAMAction(ctx, vals, nil)
+}
+
+func (c *iamClient) CreatePolicy(ctx context.Context, policyName string, path string, description string, policyDocument string) (iamPolicy, error) {
+	vals := url.Values{}
+	vals.Set("Action", "CreatePolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("PolicyName", policyName)
+	vals.Set("PolicyDocument", policyDocument)
+	if path != "" {
+		vals.Set("Path", path)
+	}
+	if description != "" {
+		vals.Set("Description", description)
+	}
+
+	var out createPolicyResponse
+	if err := c.doIAMAction(ctx, vals, &out); err != nil {
+		return iamPolicy{}, err
+	}
+	return out.Policy, nil
+}
+
+func (c *iamClient) GetPolicy(ctx context.Context, policyArn string) (iamPolicy, error) {
+	vals := url.Values{}
+	vals.Set("Action", "GetPolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("PolicyArn", policyArn)
+
+	var out getPolicyResponse
+	if err := c.doIAMAction(ctx, vals, &out); err != nil {
+		return iamPolicy{}, err
+	}
+	return out.Policy, nil
+}
+
+// ListPolicies returns the customer managed policies whose path starts with
+// pathPrefix.
+func (c *iamClient) ListPolicies(ctx context.Context, pathPrefix string) ([]iamPolicy, error) {
+	var policies []iamPolicy
+	marker := ""
+	for {
+		vals := url.Values{}
+		vals.Set("Action", "ListPolicies")
+		vals.Set("Version", "2010-05-08")
+		vals.Set("Scope", "Local")
+		if pathPrefix != "" {
+			vals.Set("PathPrefix", pathPrefix)
+		}
+		if marker != "" {
+			vals.Set("Marker", marker)
+		}
+
+		var out listPoliciesResponse
+		if err := c.doIAMAction(ctx, vals, &out); err != nil {
+			return nil, err
+		}
+		policies = append(policies, out.Policies...)
+		if !out.IsTruncated || out.Marker == "" {
+			return policies, nil
+		}
+		marker = out.Marker
+	}
+}
+
+// GetPolicyByName looks up a managed policy whose ARN is not known, such as
+// one created by a request whose response was lost. It returns NoSuchEntity
+// if no policy has that name.
+func (c *iamClient) GetPolicyByName(ctx context.Context, policyName string, pathPrefix string) (iamPolicy, error) {
+	policies, err := c.ListPolicies(ctx, pathPrefix)
+	if err != nil {
+		return iamPolicy{}, err
+	}
+	for _, policy := range policies {
+		if policy.PolicyName == policyName && policy.Arn != "" {
+			return c.GetPolicy(ctx, policy.Arn)
+		}
+	}
+	return iamPolicy{}, iamError{Code: "NoSuchEntity", Message: fmt.Sprintf("policy %s not found", policyName)}
+}
+
+func (c *iamClient) GetPolicyVersion(ctx context.Context, policyArn string, versionID string) (iamPolicyVersion, error) {
+	vals := url.Values{}
+	vals.Set("Action", "GetPolicyVersion")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("PolicyArn", policyArn)
+	vals.Set("VersionId", versionID)
+
+	var out getPolicyVersionResponse
+	if err := c.doIAMAction(ctx, vals, &out); err != nil {
+		return iamPolicyVersion{}, err
+	}
+
+	version := out.PolicyVersion
+	if decoded, err := url.QueryUnescape(version.Document); err == nil {
+		version.Document = decoded
+	}
+	return version, nil
+}
+
+func (c *iamClient) CreatePolicyVersion(ctx context.Context, policyArn string, policyDocument string, setAsDefault bool) (iamPolicyVersion, error) {
+	vals := url.Values{}
+	vals.Set("Action", "CreatePolicyVersion")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("PolicyArn", policyArn)
+	vals.Set("PolicyDocument", policyDocument)
+	if setAsDefault {
+		vals.Set("SetAsDefault", "true")
+	}
+
+	var out createPolicyVersionResponse
+	if err := c.doIAMAction(ctx, vals, &out); err != nil {
+		return iamPolicyVersion{}, err
+	}
+	return out.PolicyVersion, nil
+}
+
+func (c *iamClient) ListPolicyVersions(ctx context.Context, policyArn string) ([]iamPolicyVersion, error) {
+	var versions []iamPolicyVersion
+	marker := ""
+	for {
+		vals := url.Values{}
+		vals.Set("Action", "ListPolicyVersions")
+		vals.Set("Version", "2010-05-08")
+		vals.Set("PolicyArn", policyArn)
+		if marker != "" {
+			vals.Set("Marker", marker)
+		}
+
+		var out listPolicyVersionsResponse
+		if err := c.doIAMAction(ctx, vals, &out); err != nil {
+			return nil, err
+		}
+		versions = append(versions, out.Versions...)
+		if !out.IsTruncated || out.Marker == "" {
+			return versions, nil
+		}
+		marker = out.Marker
+	}
+}
+
+func (c *iamClient) DeletePolicyVersion(ctx context.Context, policyArn string, versionID string) error {
+	vals := url.Values{}
+	vals.Set("Action", "DeletePolicyVersion")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("PolicyArn", policyArn)
+	vals.Set("VersionId", versionID)
+
+	return c.doIAMAction(ctx, vals, nil)
+}
+
+// UpdatePolicyDocument makes policyDocument the default version of the
+// policy. The oldest non-default versions are deleted first so the new
+// version fits within the IAM version limit.
+func (c *iamClient) UpdatePolicyDocument(ctx context.Context, policyArn string, policyDocument string) (iamPolicyVersion, error) {
+	versions, err := c.ListPolicyVersions(ctx, policyArn)
+	if err != nil {
+		return iamPolicyVersion{}, err
+	}
+
+	prunable := make([]iamPolicyVersion, 0, len(versions))
+	for _, version := range versions {
+		if !version.IsDefaultVersion {
+			prunable = append(prunable, version)
+		}
+	}
+	sort.Slice(prunable, func(i, j int) bool {
+		return policyVersionOlder(prunable[i], prunable[j])
+	})
+
+	for remaining := len(versions); remaining >= maxPolicyVersions && len(prunable) > 0; remaining-- {
+		if err := c.DeletePolicyVersion(ctx, policyArn, prunable[0].VersionID); err != nil && !isNoSuchEntityError(err) {
+			return iamPolicyVersion{}, fmt.Errorf("prune policy version %s: %w", prunable[0].VersionID, err)
+		}
+		prunable = prunable[1:]
+	}
+
+	return c.CreatePolicyVersion(ctx, policyArn, policyDocument, true)
+}
+
+// DeletePolicy deletes all non-default versions and then the policy itself,
+// as IAM refuses to delete a policy that still has other versions.
+func (c *iamClient) DeletePolicy(ctx context.Context, policyArn string) error {
+	versions, err := c.ListPolicyVersions(ctx, policyArn)
+	if err != nil {
+		return err
+	}
+	for _, version := range versions {
+		if version.IsDefaultVersion {
+			continue
+		}
+		if err := c.DeletePolicyVersion(ctx, policyArn, version.VersionID); err != nil && !isNoSuchEntityError(err) {
+			return fmt.Errorf("delete policy version %s: %w", version.VersionID, err)
+		}
+	}
+
+	vals := url.Values{}
+	vals.Set("Action", "DeletePolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("PolicyArn", policyArn)
+
+	return c.doIAMAction(ctx, vals, nil)
+}
+
+func (c *iamClient) AttachUserPolicy(ctx context.Context, userName string, policyArn string) error {
+	vals := url.Values{}
+	vals.Set("Action", "AttachUserPolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("UserName", userName)
+	vals.Set("PolicyArn", policyArn)
+
+	return c.doIAMAction(ctx, vals, nil)
+}
+
+func (c *iamClient) DetachUserPolicy(ctx context.Context, userName string, policyArn string) error {
+	vals := url.Values{}
+	vals.Set("Action", "DetachUserPolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("UserName", userName)
+	vals.Set("PolicyArn", policyArn)
+
+	return c.doIAMAction(ctx, vals, nil)
+}
+
+func (c *iamClient) ListAttachedUserPolicies(ctx context.Context, userName string) ([]iamAttachedPolicy, error) {
+	var policies []iamAttachedPolicy
+	marker := ""
+	for {
+		vals := url.Values{}
+		vals.Set("Action", "ListAttachedUserPolicies")
+		vals.Set("Version", "2010-05-08")
+		vals.Set("UserName", userName)
+		if marker != "" {
+			vals.Set("Marker", marker)
+		}
+
+		var out listAttachedUserPoliciesResponse
+		if err := c.doIAMAction(ctx, vals, &out); err != nil {
+			return nil, err
+		}
+		policies = append(policies, out.AttachedPolicies...)
+		if !out.IsTruncated || out.Marker == "" {
+			return policies, nil
+		}
+		marker = out.Marker
+	}
+}
+
+func (c *iamClient) CreateRole(ctx context.Context, roleName string, path string, description string, assumeRolePolicy string) (iamRole, error) {
+	vals := url.Values{}
+	vals.Set("Action", "CreateRole")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("RoleName", roleName)
+	vals.Set("AssumeRolePolicyDocument", assumeRolePolicy)
+	if path != "" {
+		vals.Set("Path", path)
+	}
+	if description != "" {
+		vals.Set("Description", description)
+	}
+
+	var out createRoleResponse
+	if err := c.doIAMAction(ctx, vals, &out); err != nil {
+		return iamRole{}, err
+	}
+	return out.Role, nil
+}
+
+func (c *iamClient) GetRole(ctx context.Context, roleName string) (iamRole, error) {
+	vals := url.Values{}
+	vals.Set("Action", "GetRole")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("RoleName", roleName)
+
+	var out getRoleResponse
+	if err := c.doIAMAction(ctx, vals, &out); err != nil {
+		return iamRole{}, err
+	}
+
+	role := out.Role
+	if decoded, err := url.QueryUnescape(role.AssumeRolePolicyDocument); err == nil {
+		role.AssumeRolePolicyDocument = decoded
+	}
+	return role, nil
+}
+
+func (c *iamClient) UpdateAssumeRolePolicy(ctx context.Context, roleName string, assumeRolePolicy string) error {
+	vals := url.Values{}
+	vals.Set("Action", "UpdateAssumeRolePolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("RoleName", roleName)
+	vals.Set("PolicyDocument", assumeRolePolicy)
+
+	return c.doIAMAction(ctx, vals, nil)
+}
+
+func (c *iamClient) DeleteRole(ctx context.Context, roleName string) error {
+	vals := url.Values{}
+	vals.Set("Action", "DeleteRole")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("RoleName", roleName)
+
+	return c.doIAMAction(ctx, vals, nil)
+}
+
+func (c *iamClient) PutRolePolicy(ctx context.Context, roleName string, policyName string, policyDocument string) error {
+	vals := url.Values{}
+	vals.Set("Action", "PutRolePolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("RoleName", roleName)
+	vals.Set("PolicyName", policyName)
+	vals.Set("PolicyDocument", policyDocument)
+
+	return c.doIAMAction(ctx, vals, nil)
+}
+
+func (c *iamClient) GetRolePolicy(ctx context.Context, roleName string, policyName string) (string, error) {
+	vals := url.Values{}
+	vals.Set("Action", "GetRolePolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("RoleName", roleName)
+	vals.Set("PolicyName", policyName)
+
+	var out getRolePolicyResponse
+	if err := c.doIAMAction(ctx, vals, &out); err != nil {
+		return "", err
+	}
+
+	decoded, err := url.QueryUnescape(out.PolicyDocument)
+	if err != nil {
+		return out.PolicyDocument, nil
+	}
+	return decoded, nil
+}
+
+func (c *iamClient) DeleteRolePolicy(ctx context.Context, roleName string, policyName string) error {
+	vals := url.Values{}
+	vals.Set("Action", "DeleteRolePolicy")
+	vals.Set("Version", "2010-05-08")
+	vals.Set("RoleName", roleName)
+	vals.Set("PolicyName", policyName)
+
+	return c.doIAMAction(ctx, vals, nil)
+}
+
+func (c *iamClient) AssumeRole(ctx context.Context, cfg assumeRoleConfig) (aws.Credentials, error) {
+	vals := url.Values{}
+	vals.Set("Action", "AssumeRole")
+	vals.Set("Version", "2011-06-15")
+	vals.Set("RoleArn", cfg.RoleARN)
+	vals.Set("RoleSessionName", firstNonEmpty(cfg.SessionName, "terraform-provider-seaweedfs"))
+	if cfg.Duration > 0 {
+		vals.Set("DurationSeconds", fmt.Sprintf("%d", int64(cfg.Duration/time.Second)))
+	}
+
+	var out assumeRoleResponse
+	if _, err := c.doSignedRequest(ctx, "sts", http.MethodPost, c.endpoint+"/", "application/x-www-form-urlencoded", vals.Encode(), &out); err != nil {
+		return aws.Credentials{}, fmt.Errorf("assume role %s: %w", cfg.RoleARN, err)
+	}
+	if out.Credentials.AccessKeyID == "" || out.Credentials.SecretAccessKey == "" {
+		return aws.Credentials{}, fmt.Errorf("assume role %s: response contained no credentials", cfg.RoleARN)
+	}
+
+	creds := aws.Credentials{
+		AccessKeyID:     out.Credentials.AccessKeyID,
+		SecretAccessKey: out.Credentials.SecretAccessKey,
+		SessionToken:    out.Credentials.SessionToken,
+		Source:          "SeaweedFSAssumeRole",
+	}
+	if out.Credentials.Expiration != "" {
+		expires, err := time.Parse(time.RFC3339, out.Credentials.Expiration)
+		if err != nil {
+			return aws.Credentials{}, fmt.Errorf("assume role %s: parse expiration: %w", cfg.RoleARN, err)
+		}
+		creds.CanExpire = true
+		creds.Expires = expires
+	}
+	return creds, nil
+}
+
+type assumeRoleCredentialsProvider struct {
+	sts *iamClient
+	cfg assumeRoleConfig
+}
+
+func (p *assumeRoleCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	return p.sts.AssumeRole(ctx, p.cfg)
+}
+
+func (c *iamClient) CreateBucket(ctx context.Context, name string) error {
+	path := "/" + name
+	_, err := c.doSignedRequest(ctx, "s3", http.MethodPut, c.endpoint+path, "", "", nil)
+	return err
+}
+
+func (c *iamClient) HeadBucket(ctx context.Context, name string) error {
+	path := "/" + name
+	_, err := c.doSignedRequest(ctx, "s3", http.MethodHead, c.endpoint+path, "", "", nil)
+	return err
+}
+
+func (c *iamClient) DeleteBucket(ctx context.Context, name string) error {
+	path := "/" + name
+	_, err := c.doSignedRequest(ctx, "s3", http.MethodDelete, c.endpoint+path, "", "", nil)
+	return err
+}
+
+// s3BucketInfo is one entry of ListBuckets.
+type s3BucketInfo struct {
+	Name         string
+	CreationDate time.Time
+}
+
+// ListBuckets returns the buckets whose name starts with prefix, sorted by
+// name. The prefix is also applied locally because older SeaweedFS versions
+// ignore it.
+func (c *iamClient) ListBuckets(ctx context.Context, prefix string) ([]s3BucketInfo, error) {
+	var buckets []s3BucketInfo
+	input := &s3.ListBucketsInput{}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+
+	for {
+		out, err := c.s3.ListBuckets(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("list buckets: %w", err)
+		}
+		for _, bucket := range out.Buckets {
+			name := aws.ToString(bucket.Name)
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			buckets = append(buckets, s3BucketInfo{Name: name, CreationDate: aws.ToTime(bucket.CreationDate)})
+		}
+
+		token := aws.ToString(out.ContinuationToken)
+		if token == "" || token == aws.ToString(input.ContinuationToken) {
+			break
+		}
+		input.ContinuationToken = aws.String(token)
+	}
+
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Name < buckets[j].Name })
+	return buckets, nil
+}
+
+func (c *iamClient) GetBucketTags(ctx context.Context, name string) (map[string]string, error) {
+	out, err := c.s3.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		if isNoSuchTagSetError(err) {
+			return map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("get bucket tagging: %w", err)
+	}
+
+	tags := make(map[string]string, len(out.TagSet))
+	for _, tag := range out.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+func (c *iamClient) PutBucketTags(ctx context.Context, name string, tags map[string]string) error {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tagSet := make([]s3types.Tag, 0, len(tags))
+	for _, key := range keys {
+		value := tags[key]
+		tagSet = append(tagSet, s3types.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(value),
+		})
+	}
+
+	_, err := c.s3.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+		Bucket: aws.String(name),
+		Tagging: &s3types.Tagging{
+			TagSet: tagSet,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("put bucket tagging: %w", err)
+	}
+	return nil
+}
+
+func (c *iamClient) DeleteBucketTags(ctx context.Context, name string) error {
+	_, err := c.s3.DeleteBucketTagging(ctx, &s3.DeleteBucketTaggingInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("delete bucket tagging: %w", err)
+	}
+	return nil
+}
+
+func (c *iamClient) GetBucketVersioning(ctx context.Context, name string) (string, error) {
+	out, err := c.s3.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		return "", fmt.Errorf("get bucket versioning: %w", err)
+	}
+	return string(out.Status), nil
+}
+
+func (c *iamClient) PutBucketVersioning(ctx context.Context, name string, status string) error {
+	_, err := c.s3.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+		Bucket: aws.String(name),
+		VersioningConfiguration: &s3types.VersioningConfiguration{
+			Status: s3types.BucketVersioningStatus(status),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("put bucket versioning: %w", err)
+	}
+	return nil
+}
+
+func (c *iamClient) GetBucketLifecycleRules(ctx context.Context, name string) ([]bucketLifecycleRule, error) {
+	out, err := c.s3.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		if isNoSuchLifecycleConfigurationError(err) {
+			return []bucketLifecycleRule{}, nil
+		}
+		return nil, fmt.Errorf("get bucket lifecycle configuration: %w", err)
+	}
+
+	rules := make([]bucketLifecycleRule, 0, len(out.Rules))
+	for _, in := range out.Rules {
+		rule := bucketLifecycleRule{
+			ID:     aws.ToString(in.ID),
+			Status: string(in.Status),
+			Prefix: aws.ToString(in.Prefix),
+			Tags:   map[string]string{},
+		}
+		if in.Filter != nil {
+			if in.Filter.Prefix != nil {
+				rule.Prefix = aws.ToString(in.Filter.Prefix)
+			}
+			if in.Filter.Tag != nil {
+				rule.Tags[aws.ToString(in.Filter.Tag.Key)] = aws.ToString(in.Filter.Tag.Value)
+			}
+			if in.Filter.And != nil {
+				if in.Filter.And.Prefix != nil {
+					rule.Prefix = aws.ToString(in.Filter.And.Prefix)
+				}
+				for _, tag := range in.Filter.And.Tags {
+					rule.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				}
+			}
+		}
+		if in.Expiration != nil {
+			rule.ExpirationDays = aws.ToInt32(in.Expiration.Days)
+		}
+		if in.NoncurrentVersionExpiration != nil {
+			rule.NoncurrentVersionExpirationDays = aws.ToInt32(in.NoncurrentVersionExpiration.NoncurrentDays)
+		}
+		if in.AbortIncompleteMultipartUpload != nil {
+			rule.AbortIncompleteMultipartUploadDays = aws.ToInt32(in.AbortIncompleteMultipartUpload.DaysAfterInitiation)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (c *iamClient) PutBucketLifecycleRules(ctx context.Context, name string, rules []bucketLifecycleRule) error {
+	out := make([]s3types.LifecycleRule, 0, len(rules))
+	for _, rule := range rules {
+		item := s3types.LifecycleRule{
+			ID:     aws.String(rule.ID),
+			Status: s3types.ExpirationStatus(rule.Status),
+			Filter: lifecycleRuleFilter(rule.Prefix, rule.Tags),
+		}
+		if rule.ExpirationDays > 0 {
+			item.Expiration = &s3types.LifecycleExpiration{
+				Days: aws.Int32(rule.ExpirationDays),
+			}
+		}
+		if rule.NoncurrentVersionExpirationDays > 0 {
+			item.NoncurrentVersionExpiration = &s3types.NoncurrentVersionExpiration{
+				NoncurrentDays: aws.Int32(rule.NoncurrentVersionExpirationDays),
+			}
+		}
+		if rule.AbortIncompleteMultipartUploadDays > 0 {
+			item.AbortIncompleteMultipartUpload = &s3types.AbortIncompleteMultipartUpload{
+				DaysAfterInitiation: aws.Int32(rule.AbortIncompleteMultipartUploadDays),
+			}
+		}
+		out = append(out, item)
+	}
+
+	_, err := c.s3.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(name),
+		LifecycleConfiguration: &s3types.BucketLifecycleConfiguration{
+			Rules: out,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("put bucket lifecycle configuration: %w", err)
+	}
+	return nil
+}
+
+func (c *iamClient) DeleteBucketLifecycle(ctx context.Context, name string) error {
+	_, err := c.s3.DeleteBucketLifecycle(ctx, &s3.DeleteBucketLifecycleInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("delete bucket lifecycle configuration: %w", err)
+	}
+	return nil
+}
+
+func (c *iamClient) GetBucketPolicy(ctx context.Context, name string) (string, error) {
+	out, err := c.s3.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		if isNoSuchBucketPolicyError(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("get bucket policy: %w", err)
+	}
+	return aws.ToString(out.Policy), nil
+}
+
+func (c *iamClient) PutBucketPolicy(ctx context.Context, name string, policy string) error {
+	_, err := c.s3.PutBucketPolicy(ctx, &s3.PutBucketPolicyInput{
+		Bucket: aws.String(name),
+		Policy: aws.String(policy),
+	})
+	if err != nil {
+		return fmt.Errorf("put bucket policy: %w", err)
+	}
+	return nil
+}
+
+func (c *iamClient) DeleteBucketPolicy(ctx context.Context, name string) error {
+	_, err := c.s3.DeleteBucketPolicy(ctx, &s3.DeleteBucketPolicyInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("delete bucket policy: %w", err)
+	}
+	return nil
+}
+
+func (c *iamClient) GetBucketCORSRules(ctx context.Context, name string) ([]bucketCORSRule, error) {
+	out, err := c.s3.GetBucketCors(ctx, &s3.GetBucketCorsInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		if isNoSuchCORSConfigurationError(err) {
+			return []bucketCORSRule{}, nil
+		}
+		return nil, fmt.Errorf("get bucket cors: %w", err)
+	}
+
+	rules := make([]bucketCORSRule, 0, len(out.CORSRules))
+	for _, in := range out.CORSRules {
+		rules = append(rules, bucketCORSRule{
+			ID:             aws.ToString(in.ID),
+			AllowedHeaders: in.AllowedHeaders,
+			AllowedMethods: in.AllowedMethods,
+			AllowedOrigins: in.AllowedOrigins,
+			ExposeHeaders:  in.ExposeHeaders,
+			MaxAgeSeconds:  aws.ToInt32(in.MaxAgeSeconds),
+		})
+	}
+	return rules, nil
+}
+
+func (c *iamClient) PutBucketCORSRules(ctx context.Context, name string, rules []bucketCORSRule) error {
+	out := make([]s3types.CORSRule, 0, len(rules))
+	for _, rule := range rules {
+		item := s3types.CORSRule{
+			AllowedHeaders: rule.AllowedHeaders,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedOrigins: rule.AllowedOrigins,
+			ExposeHeaders:  rule.ExposeHeaders,
+		}
+		if rule.ID != "" {
+			item.ID = aws.String(rule.ID)
+		}
+		if rule.MaxAgeSeconds > 0 {
+			item.MaxAgeSeconds = aws.Int32(rule.MaxAgeSeconds)
+		}
+		out = append(out, item)
+	}
+
+	_, err := c.s3.PutBucketCors(ctx, &s3.PutBucketCorsInput{
+		Bucket: aws.String(name),
+		CORSConfiguration: &s3types.CORSConfiguration{
+			CORSRules: out,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("put bucket cors: %w", err)
+	}
+	return nil
+}
+
+func (c *iamClient) DeleteBucketCORS(ctx context.Context, name string) error {
+	_, err := c.s3.DeleteBucketCors(ctx, &s3.DeleteBucketCorsInput{
+		Bucket: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("delete bucket cors: %w", err)
+	}
+	return nil
+}
+
+// EmptyBucket aborts in-flight multipart uploads and deletes every object in
+// the bucket. Versioned buckets are emptied version by version, including
+// delete markers. Listing and deleting run concurrently.
+func (c *iamClient) EmptyBucket(ctx context.Context, name string) error {
+	if err := c.abortMultipartUploads(ctx, name); err != nil {
+		return err
+	}
+
+	versioning, err := c.GetBucketVersioning(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	batches := make(chan []s3types.ObjectIdentifier)
+	deleteErr := make(chan error, 1)
+	go func() {
+		deleteErr <- c.deleteObjectBatches(ctx, cancel, name, batches)
+	}()
+
+	var listErr error
+	if versioning == "" {
+		listErr = c.listObjectBatches(ctx, name, batches)
+	} else {
+		listErr = c.listObjectVersionBatches(ctx, name, batches)
+	}
+	close(batches)
+
+	if err := <-deleteErr; err != nil {
+		return err
+	}
+	return listErr
+}
+
+func (c *iamClient) abortMultipartUploads(ctx context.Context, name string) error {
+	input := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(name),
+	}
+	for {
+		out, err := c.s3.ListMultipartUploads(ctx, input)
+		if err != nil {
+			return fmt.Errorf("list multipart uploads: %w", err)
+		}
+
+		for _, upload := range out.Uploads {
+			_, err := c.s3.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(name),
+				Key:      upload.Key,
+				UploadId: upload.UploadId,
+			})
+			if err != nil && !isNoSuchUploadError(err) {
+				return fmt.Errorf("abort multipart upload %s: %w", aws.ToString(upload.Key), err)
+			}
+		}
+
+		if !aws.ToBool(out.IsTruncated) {
+			return nil
+		}
+		input.KeyMarker = out.NextKeyMarker
+		input.UploadIdMarker = out.NextUploadIdMarker
+	}
+}
+
+func (c *iamClient) listObjectBatches(ctx context.Context, name string, batches chan<- []s3types.ObjectIdentifier) error {
+	paginator := s3.NewListObjectsV2Paginator(c.s3, &s3.ListObjectsV2Input{
+		Bucket:  aws.String(name),
+		MaxKeys: aws.Int32(deleteObjectsBatchSize),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("list objects: %w", err)
+		}
+
+		batch := make([]s3types.ObjectIdentifier, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			batch = append(batch, s3types.ObjectIdentifier{Key: object.Key})
+		}
+		if err := sendObjectBatch(ctx, batches, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *iamClient) listObjectVersionBatches(ctx context.Context, name string, batches chan<- []s3types.ObjectIdentifier) error {
+	input := &s3.ListObjectVersionsInput{
+		Bucket:  aws.String(name),
+		MaxKeys: aws.Int32(deleteObjectsBatchSize),
+	}
+	for {
+		out, err := c.s3.ListObjectVersions(ctx, input)
+		if err != nil {
+			return fmt.Errorf("list object versions: %w", err)
+		}
+
+		batch := make([]s3types.ObjectIdentifier, 0, len(out.Versions)+len(out.DeleteMarkers))
+		for _, version := range out.Versions {
+			batch = append(batch, s3types.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, marker := range out.DeleteMarkers {
+			batch = append(batch, s3types.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+		}
+		for len(batch) > 0 {
+			n := min(len(batch), deleteObjectsBatchSize)
+			if err := sendObjectBatch(ctx, batches, batch[:n]); err != nil {
+				return err
+			}
+			batch = batch[n:]
+		}
+
+		if !aws.ToBool(out.IsTruncated) {
+			return nil
+		}
+		input.KeyMarker = out.NextKeyMarker
+		input.VersionIdMarker = out.NextVersionIdMarker
+	}
+}
+
+func sendObjectBatch(ctx context.Context, batches chan<- []s3types.ObjectIdentifier, batch []s3types.ObjectIdentifier) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	select {
+	case batches <- batch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *iamClient) deleteObjectBatches(ctx context.Context, cancel context.CancelFunc, name string, batches <-chan []s3types.ObjectIdentifier) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	for range deleteObjectsConcurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				if err := c.deleteObjectBatch(ctx, name, batch); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+					cancel()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func (c *iamClient) deleteObjectBatch(ctx context.Context, name string, batch []s3types.ObjectIdentifier) error {
+	out, err := c.s3.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(name),
+		Delete: &s3types.Delete{
+			Objects: batch,
+			Quiet:   aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("delete objects: %w", err)
+	}
+	if len(out.Errors) > 0 {
+		first := out.Errors[0]
+		return fmt.Errorf(
+			"delete objects: %d of %d objects failed, first error on %q: %s: %s",
+			len(out.Errors),
+			len(batch),
+			aws.ToString(first.Key),
+			aws.ToString(first.Code),
+			aws.ToString(first.Message),
+		)
+	}
+	return nil
+}
+
+// PutObject uploads size bytes from body. Bodies larger than s3ObjectPartSize
+// are uploaded with multipart upload; the upload is aborted on failure.
+func (c *iamClient) PutObject(ctx context.Context, in s3ObjectInput, body io.ReaderAt, size int64) (s3ObjectInfo, error) {
+	if size > s3ObjectPartSize {
+		return c.putObjectMultipart(ctx, in, body, size)
+	}
+
+	out, err := c.s3.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(in.Bucket),
+		Key:           aws.String(in.Key),
+		Body:          io.NewSectionReader(body, 0, size),
+		ContentLength: aws.Int64(size),
+		ContentType:   optionalString(in.ContentType),
+		CacheControl:  optionalString(in.CacheControl),
+		Metadata:      in.Metadata,
+	})
+	if err != nil {
+		return s3ObjectInfo{}, fmt.Errorf("put object: %w", err)
+	}
+
+	return s3ObjectInfo{
+		ETag:      strings.Trim(aws.ToString(out.ETag), `"`),
+		VersionID: aws.ToString(out.VersionId),
+		Size:      size,
+	}, nil
+}
+
+func (c *iamClient) putObjectMultipart(ctx context.Context, in s3ObjectInput, body io.ReaderAt, size int64) (s3ObjectInfo, error) {
+	created, err := c.s3.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:       aws.String(in.Bucket),
+		Key:          aws.String(in.Key),
+		ContentType:  optionalString(in.ContentType),
+		CacheControl: optionalString(in.CacheControl),
+		Metadata:     in.Metadata,
+	})
+	if err != nil {
+		return s3ObjectInfo{}, fmt.Errorf("create multipart upload: %w", err)
+	}
+
+	abort := func(cause error) (s3ObjectInfo, error) {
+		_, abortErr := c.s3.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(in.Bucket),
+			Key:      aws.String(in.Key),
+			UploadId: created.UploadId,
+		})
+		if abortErr != nil && !isNoSuchUploadError(abortErr) {
+			return s3ObjectInfo{}, errors.Join(cause, fmt.Errorf("abort multipart upload: %w", abortErr))
+		}
+		return s3ObjectInfo{}, cause
+	}
+
+	var parts []s3types.CompletedPart
+	for offset, partNumber := int64(0), int32(1); offset < size; offset, partNumber = offset+s3ObjectPartSize, partNumber+1 {
+		length := min(int64(s3ObjectPartSize), size-offset)
+		out, err := c.s3.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:        aws.String(in.Bucket),
+			Key:           aws.String(in.Key),
+			UploadId:      created.UploadId,
+			PartNumber:    aws.Int32(partNumber),
+			Body:          io.NewSectionReader(body, offset, length),
+			ContentLength: aws.Int64(length),
+		})
+		if err != nil {
+			return abort(fmt.Errorf("upload part %d: %w", partNumber, err))
+		}
+		parts = append(parts, s3types.CompletedPart{
+			ETag:       out.ETag,
+			PartNumber: aws.Int32(partNumber),
+		})
+	}
+
+	out, err := c.s3.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String(in.Bucket),
+		Key:      aws.String(in.Key),
+		UploadId: created.UploadId,
+		MultipartUpload: &s3types.CompletedMultipartUpload{
+			Parts: parts,
+		},
+	})
+	if err != nil {
+		return abort(fmt.Errorf("complete multipart upload: %w", err))
+	}
+
+	return s3ObjectInfo{
+		ETag:      strings.Trim(aws.ToString(out.ETag), `"`),
+		VersionID: aws.ToString(out.VersionId),
+		Size:      size,
+	}, nil
+}
+
+func (c *iamClient) HeadObject(ctx context.Context, bucket string, key string) (s3ObjectInfo, error) {
+	out, err := c.s3.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return s3ObjectInfo{}, fmt.Errorf("head object: %w", err)
+	}
+
+	metadata := out.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return s3ObjectInfo{
+		ETag:         strings.Trim(aws.ToString(out.ETag), `"`),
+		VersionID:    aws.ToString(out.VersionId),
+		ContentType:  aws.ToString(out.ContentType),
+		CacheControl: aws.ToString(out.CacheControl),
+		Metadata:     metadata,
+		Size:         aws.ToInt64(out.ContentLength),
+	}, nil
+}
+
+func (c *iamClient) DeleteObject(ctx context.Context, bucket string, key string) error {
+	_, err := c.s3.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("delete object: %w", err)
+	}
+	return nil
+}
+
+// computeObjectETag returns the ETag S3 reports for size bytes of body when
+// uploaded by PutObject: the MD5 for single-part uploads, or the MD5 of the
+// concatenated part MD5s suffixed with the part count for multipart uploads.
+func computeObjectETag(body io.ReaderAt, size int64) (string, error) {
+	if size <= s3ObjectPartSize {
+		sum := md5.New() //nolint:gosec
+		if _, err := io.Copy(sum, io.NewSectionReader(body, 0, size)); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(sum.Sum(nil)), nil
+	}
+
+	var partSums []byte
+	parts := 0
+	for offset := int64(0); offset < size; offset += s3ObjectPartSize {
+		length := min(int64(s3ObjectPartSize), size-offset)
+		sum := md5.New() //nolint:gosec
+		if _, err := io.Copy(sum, io.NewSectionReader(body, offset, length)); err != nil {
+			return "", err
+		}
+		partSums = sum.Sum(partSums)
+		parts++
+	}
+	total := md5.Sum(partSums) //nolint:gosec
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(total[:]), parts), nil
+}
+
+// policyVersionOlder orders policy versions by creation date, falling back to
+// the numeric part of version ids such as "v3".
+func policyVersionOlder(a iamPolicyVersion, b iamPolicyVersion) bool {
+	if a.CreateDate != "" && b.CreateDate != "" && a.CreateDate != b.CreateDate {
+		return a.CreateDate < b.CreateDate
+	}
+
+	var na, nb int
+	_, errA := fmt.Sscanf(a.VersionID, "v%d", &na)
+	_, errB := fmt.Sscanf(b.VersionID, "v%d", &nb)
+	if errA == nil && errB == nil {
+		return na < nb
+	}
+	return a.VersionID < b.VersionID
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return aws.String(value)
+}
+
+func lifecycleRuleFilter(prefix string, tags map[string]string) *s3types.LifecycleRuleFilter {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tagSet := make([]s3types.Tag, 0, len(tags))
+	for _, key := range keys {
+		tagSet = append(tagSet, s3types.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(tags[key]),
+		})
+	}
+
+	switch {
+	case len(tagSet) == 0:
+		return &s3types.LifecycleRuleFilter{Prefix: aws.String(prefix)}
+	case len(tagSet) == 1 && prefix == "":
+		return &s3types.LifecycleRuleFilter{Tag: &tagSet[0]}
+	default:
+		and := &s3types.LifecycleRuleAndOperator{Tags: tagSet}
+		if prefix != "" {
+			and.Prefix = aws.String(prefix)
+		}
+		return &s3types.LifecycleRuleFilter{And: and}
+	}
+}
+
+func (c *iamClient) doIAMAction(ctx context.Context, form url.Values, out any) error {
+	body := form.Encode()
+	_, err := c.doSignedRequest(
+		ctx,
+		"iam",
+		http.MethodPost,
+		c.endpoint+"/",
+		"application/x-www-form-urlencoded",
+		body,
+		out,
+	)
+	return err
+}
+
+func (c *iamClient) doSignedRequest(
+	ctx context.Context,
+	service string,
+	method string,
+	requestURL string,
+	contentType string,
+	body string,
+	out any,
+) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewBufferString(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Host", req.URL.Host)
+
+	creds, err := c.creds.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve credentials: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(body))
+	hash := fmt.Sprintf("%x", sum)
+	ctx = v4.SetPayloadHash(ctx, hash)
 
 	if err := c.signer.SignHTTP(ctx, creds, req, hash, service, c.region, time.Now()); err != nil {
 		return nil, fmt.Errorf("sign request: %w", err)
@@ -476,6 +1965,28 @@ func isEntityAlreadyExistsError(err error) bool {
 	return false
 }
 
+func isNotImplementedError(err error) bool {
+	var apiErr iamError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == "NotImplemented" || apiErr.Code == "HTTP501"
+	}
+	var sdkErr smithy.APIError
+	if errors.As(err, &sdkErr) {
+		return sdkErr.ErrorCode() == "NotImplemented"
+	}
+	return false
+}
+
+// iamErrorDetail describes err for a diagnostic. Servers without support for
+// an IAM action answer with NotImplemented, which is reported in plain words
+// instead of as a raw HTTP status.
+func iamErrorDetail(err error) string {
+	if isNotImplementedError(err) {
+		return fmt.Sprintf("The SeaweedFS server does not implement this IAM action. Upgrade SeaweedFS to a version that supports it, or remove the resource from the configuration. (%s)", err)
+	}
+	return err.Error()
+}
+
 func isServiceFailureError(err error) bool {
 	var apiErr iamError
 	if errors.As(err, &apiErr) {
@@ -532,6 +2043,10 @@ func isNoSuchBucketError(err error) bool {
 	if errors.As(err, &apiErr) {
 		return apiErr.Code == "NoSuchBucket" || apiErr.Code == "NotFound" || apiErr.Code == "NoSuchKey" || apiErr.Code == "NoSuchEntity"
 	}
+	var sdkErr smithy.APIError
+	if errors.As(err, &sdkErr) {
+		return sdkErr.ErrorCode() == "NoSuchBucket"
+	}
 	return false
 }
 
@@ -550,3 +2065,43 @@ func isNoSuchTagSetError(err error) bool {
 	}
 	return false
 }
+
+func isNoSuchLifecycleConfigurationError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode() == "NoSuchLifecycleConfiguration"
+	}
+	return false
+}
+
+func isNoSuchBucketPolicyError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode() == "NoSuchBucketPolicy"
+	}
+	return false
+}
+
+func isNoSuchCORSConfigurationError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode() == "NoSuchCORSConfiguration"
+	}
+	return false
+}
+
+func isNoSuchUploadError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode() == "NoSuchUpload"
+	}
+	return false
+}
+
+func isNoSuchKeyError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode() == "NoSuchKey" || apiErr.ErrorCode() == "NotFound"
+	}
+	return false
+}
@@ -1,15 +1,33 @@
 package seaweedfs
 
 import (
+	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/md5" //nolint:gosec
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestIAMClientUserLifecycle(t *testing.T) {
@@ -129,6 +147,161 @@ func TestIAMClientCreateFromServiceFailure(t *testing.T) {
 	}
 }
 
+func TestIAMClientMutualTLS(t *testing.T) {
+	t.Parallel()
+
+	clientCertPEM, clientKeyPEM, clientCAs := newTestClientCertificate(t)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`<GetUserResponse><GetUserResult><User><UserName>alice</UserName></User></GetUserResult></GetUserResponse>`))
+	}))
+	srv.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	serverCAPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+
+	tests := []struct {
+		name    string
+		cfg     iamClientConfig
+		wantErr bool
+	}{
+		{
+			name: "ca and client certificate",
+			cfg:  iamClientConfig{CACertPEM: serverCAPEM, ClientCertPEM: clientCertPEM, ClientKeyPEM: clientKeyPEM},
+		},
+		{
+			name:    "missing client certificate",
+			cfg:     iamClientConfig{CACertPEM: serverCAPEM},
+			wantErr: true,
+		},
+		{
+			name:    "untrusted server certificate",
+			cfg:     iamClientConfig{ClientCertPEM: clientCertPEM, ClientKeyPEM: clientKeyPEM},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := tt.cfg
+			cfg.Endpoint = srv.URL
+			cfg.AccessKey = "test-key"
+			cfg.SecretKey = "test-secret"
+			client, err := newIAMClient(cfg)
+			if err != nil {
+				t.Fatalf("new client: %v", err)
+			}
+
+			user, err := client.GetUser(context.Background(), "alice")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected TLS error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("get user over mTLS: %v", err)
+			}
+			if user.User.UserName != "alice" {
+				t.Fatalf("unexpected user: %+v", user)
+			}
+		})
+	}
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	certPEM, keyPEM, _ := newTestClientCertificate(t)
+	otherCertPEM, _, _ := newTestClientCertificate(t)
+
+	tests := []struct {
+		name string
+		cfg  iamClientConfig
+	}{
+		{name: "invalid ca", cfg: iamClientConfig{CACertPEM: "not a certificate"}},
+		{name: "cert without key", cfg: iamClientConfig{ClientCertPEM: certPEM}},
+		{name: "key without cert", cfg: iamClientConfig{ClientKeyPEM: keyPEM}},
+		{name: "mismatched key", cfg: iamClientConfig{ClientCertPEM: otherCertPEM, ClientKeyPEM: keyPEM}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := newTLSConfig(tt.cfg); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+
+	if cfg, err := newTLSConfig(iamClientConfig{}); err != nil || cfg != nil {
+		t.Fatalf("expected default TLS config, got %+v, %v", cfg, err)
+	}
+}
+
+// newTestClientCertificate returns a PEM client certificate and key signed by
+// a fresh CA, and a pool containing that CA.
+func newTestClientCertificate(t *testing.T) (string, string, *x509.CertPool) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ca key: %v", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("create ca certificate: %v", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("parse ca certificate: %v", err)
+	}
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate client key: %v", err)
+	}
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caCert, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("create client certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(clientKey)
+	if err != nil {
+		t.Fatalf("marshal client key: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM), pool
+}
+
 func TestIAMClientAccessKeyPolicyAndBucket(t *testing.T) {
 	t.Parallel()
 
@@ -357,25 +530,2085 @@ func TestIAMClientAccessKeyPolicyAndBucket(t *testing.T) {
 	}
 }
 
-func TestPoliciesSemanticallyEqual(t *testing.T) {
+func TestIAMClientUpdateAccessKey(t *testing.T) {
 	t.Parallel()
 
-	a := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":"*"}]}`
-	b := `{
-  "Statement": [
-    {
-      "Resource": "*",
-      "Action": [
-        "s3:*"
-      ],
-      "Effect": "Allow"
-    }
-  ],
-  "Version": "2012-10-17"
-}`
+	keyStatus := map[string]string{"AKIA_TEST": "Active"}
 
-	if !policiesSemanticallyEqual(a, b) {
-		t.Fatalf("expected policies to be semantically equal")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse form body: %v", err)
+		}
+		if form.Get("UserName") != "alice" {
+			t.Fatalf("unexpected user: %s", form.Get("UserName"))
+		}
+
+		switch form.Get("Action") {
+		case "UpdateAccessKey":
+			if _, ok := keyStatus[form.Get("AccessKeyId")]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>NoSuchEntity</Code><Message>Not found</Message></Error></ErrorResponse>`))
+				return
+			}
+			keyStatus[form.Get("AccessKeyId")] = form.Get("Status")
+			_, _ = w.Write([]byte(`<UpdateAccessKeyResponse/>`))
+		case "ListAccessKeys":
+			_, _ = w.Write([]byte(`<ListAccessKeysResponse><ListAccessKeysResult><AccessKeyMetadata><member><UserName>alice</UserName><AccessKeyId>AKIA_TEST</AccessKeyId><Status>` + keyStatus["AKIA_TEST"] + `</Status></member></AccessKeyMetadata></ListAccessKeysResult></ListAccessKeysResponse>`))
+		default:
+			t.Fatalf("unexpected action: %s", form.Get("Action"))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if err := client.UpdateAccessKey(ctx, "alice", "AKIA_TEST", "Inactive"); err != nil {
+		t.Fatalf("update access key: %v", err)
+	}
+	list, err := client.ListAccessKeys(ctx, "alice")
+	if err != nil {
+		t.Fatalf("list access keys: %v", err)
+	}
+	if len(list) != 1 || list[0].AccessKeyID != "AKIA_TEST" || list[0].Status != "Inactive" {
+		t.Fatalf("expected inactive key after update, got: %+v", list)
+	}
+
+	if err := client.UpdateAccessKey(ctx, "alice", "AKIA_TEST", "Active"); err != nil {
+		t.Fatalf("reactivate access key: %v", err)
+	}
+	list, err = client.ListAccessKeys(ctx, "alice")
+	if err != nil {
+		t.Fatalf("list access keys after reactivation: %v", err)
+	}
+	if len(list) != 1 || list[0].Status != "Active" {
+		t.Fatalf("expected active key after reactivation, got: %+v", list)
+	}
+
+	if err := client.UpdateAccessKey(ctx, "alice", "AKIA_OTHER", "Active"); !isNoSuchEntityError(err) {
+		t.Fatalf("expected NoSuchEntity for unknown key, got: %v", err)
+	}
+}
+
+func TestIAMClientGroups(t *testing.T) {
+	t.Parallel()
+
+	groups := map[string][]string{}
+	groupPolicies := map[string]string{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse form body: %v", err)
+		}
+
+		group := form.Get("GroupName")
+		notFound := func() {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>NoSuchEntity</Code><Message>Not found</Message></Error></ErrorResponse>`))
+		}
+
+		switch form.Get("Action") {
+		case "CreateGroup":
+			if _, ok := groups[group]; ok {
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>EntityAlreadyExists</Code><Message>exists</Message></Error></ErrorResponse>`))
+				return
+			}
+			groups[group] = []string{}
+			_, _ = w.Write([]byte(`<CreateGroupResponse><CreateGroupResult><Group><Path>/</Path><GroupName>` + group + `</GroupName><GroupId>gid-1</GroupId><Arn>arn:aws:iam::000000000000:group/` + group + `</Arn></Group></CreateGroupResult></CreateGroupResponse>`))
+		case "GetGroup":
+			members, ok := groups[group]
+			if !ok {
+				notFound()
+				return
+			}
+			// Return one member per page to exercise pagination.
+			start := 0
+			if marker := form.Get("Marker"); marker != "" {
+				_, _ = fmt.Sscanf(marker, "m%d", &start)
+			}
+			users := ""
+			truncated := ""
+			if start < len(members) {
+				users = `<member><UserName>` + members[start] + `</UserName></member>`
+				if start+1 < len(members) {
+					truncated = fmt.Sprintf(`<IsTruncated>true</IsTruncated><Marker>m%d</Marker>`, start+1)
+				}
+			}
+			_, _ = w.Write([]byte(`<GetGroupResponse><GetGroupResult><Group><Path>/</Path><GroupName>` + group + `</GroupName><GroupId>gid-1</GroupId></Group><Users>` + users + `</Users>` + truncated + `</GetGroupResult></GetGroupResponse>`))
+		case "DeleteGroup":
+			if _, ok := groups[group]; !ok {
+				notFound()
+				return
+			}
+			delete(groups, group)
+			_, _ = w.Write([]byte(`<DeleteGroupResponse/>`))
+		case "AddUserToGroup":
+			if _, ok := groups[group]; !ok {
+				notFound()
+				return
+			}
+			groups[group] = append(groups[group], form.Get("UserName"))
+			_, _ = w.Write([]byte(`<AddUserToGroupResponse/>`))
+		case "RemoveUserFromGroup":
+			members := groups[group]
+			for i, member := range members {
+				if member == form.Get("UserName") {
+					groups[group] = append(members[:i], members[i+1:]...)
+					_, _ = w.Write([]byte(`<RemoveUserFromGroupResponse/>`))
+					return
+				}
+			}
+			notFound()
+		case "PutGroupPolicy":
+			groupPolicies[group+":"+form.Get("PolicyName")] = form.Get("PolicyDocument")
+			_, _ = w.Write([]byte(`<PutGroupPolicyResponse/>`))
+		case "GetGroupPolicy":
+			policy, ok := groupPolicies[group+":"+form.Get("PolicyName")]
+			if !ok {
+				notFound()
+				return
+			}
+			_, _ = w.Write([]byte(`<GetGroupPolicyResponse><GetGroupPolicyResult><GroupName>` + group + `</GroupName><PolicyName>` + form.Get("PolicyName") + `</PolicyName><PolicyDocument>` + url.QueryEscape(policy) + `</PolicyDocument></GetGroupPolicyResult></GetGroupPolicyResponse>`))
+		case "DeleteGroupPolicy":
+			delete(groupPolicies, group+":"+form.Get("PolicyName"))
+			_, _ = w.Write([]byte(`<DeleteGroupPolicyResponse/>`))
+		default:
+			t.Fatalf("unexpected action: %s", form.Get("Action"))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	group, err := client.CreateGroup(ctx, "readers", "/")
+	if err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	if group.GroupName != "readers" || group.GroupID != "gid-1" {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+	if _, err := client.CreateGroup(ctx, "readers", "/"); !isEntityAlreadyExistsError(err) {
+		t.Fatalf("expected EntityAlreadyExists, got: %v", err)
+	}
+
+	for _, user := range []string{"alice", "bob", "carol"} {
+		if err := client.AddUserToGroup(ctx, "readers", user); err != nil {
+			t.Fatalf("add %s to group: %v", user, err)
+		}
+	}
+	if err := client.RemoveUserFromGroup(ctx, "readers", "bob"); err != nil {
+		t.Fatalf("remove user from group: %v", err)
+	}
+
+	_, members, err := client.GetGroup(ctx, "readers")
+	if err != nil {
+		t.Fatalf("get group: %v", err)
+	}
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		names = append(names, member.UserName)
+	}
+	if !reflect.DeepEqual(names, []string{"alice", "carol"}) {
+		t.Fatalf("unexpected members: %v", names)
+	}
+
+	policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+	if err := client.PutGroupPolicy(ctx, "readers", "read", policy); err != nil {
+		t.Fatalf("put group policy: %v", err)
+	}
+	got, err := client.GetGroupPolicy(ctx, "readers", "read")
+	if err != nil {
+		t.Fatalf("get group policy: %v", err)
+	}
+	if got != policy {
+		t.Fatalf("unexpected group policy: %s", got)
+	}
+	if err := client.DeleteGroupPolicy(ctx, "readers", "read"); err != nil {
+		t.Fatalf("delete group policy: %v", err)
+	}
+	if _, err := client.GetGroupPolicy(ctx, "readers", "read"); !isNoSuchEntityError(err) {
+		t.Fatalf("expected NoSuchEntity after policy delete, got: %v", err)
+	}
+
+	if err := client.DeleteGroup(ctx, "readers"); err != nil {
+		t.Fatalf("delete group: %v", err)
+	}
+	if _, _, err := client.GetGroup(ctx, "readers"); !isNoSuchEntityError(err) {
+		t.Fatalf("expected NoSuchEntity after group delete, got: %v", err)
+	}
+}
+
+func TestIAMClientListUsers(t *testing.T) {
+	t.Parallel()
+
+	// Pages of two users, chained with markers.
+	users := []string{"alice", "bob", "carol", "dave", "erin"}
+	var markers []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse form body: %v", err)
+		}
+
+		switch form.Get("Action") {
+		case "ListUsers":
+			if form.Get("PathPrefix") != "/team/" {
+				t.Fatalf("unexpected path prefix: %q", form.Get("PathPrefix"))
+			}
+			markers = append(markers, form.Get("Marker"))
+			start := 0
+			if marker := form.Get("Marker"); marker != "" {
+				fmt.Sscanf(marker, "page-%d", &start)
+			}
+			end := min(start+2, len(users))
+			members := ""
+			for _, user := range users[start:end] {
+				members += `<member><UserName>` + user + `</UserName><Path>/team/</Path><UserId>id-` + user + `</UserId><Arn>arn:aws:iam::000000000000:user/team/` + user + `</Arn></member>`
+			}
+			more := ""
+			if end < len(users) {
+				more = fmt.Sprintf(`<IsTruncated>true</IsTruncated><Marker>page-%d</Marker>`, end)
+			}
+			_, _ = w.Write([]byte(`<ListUsersResponse><ListUsersResult><Users>` + members + `</Users>` + more + `</ListUsersResult></ListUsersResponse>`))
+		case "ListUserPolicies":
+			if form.Get("Marker") == "" {
+				_, _ = w.Write([]byte(`<ListUserPoliciesResponse><ListUserPoliciesResult><PolicyNames><member>read</member></PolicyNames><IsTruncated>true</IsTruncated><Marker>next</Marker></ListUserPoliciesResult></ListUserPoliciesResponse>`))
+				return
+			}
+			_, _ = w.Write([]byte(`<ListUserPoliciesResponse><ListUserPoliciesResult><PolicyNames><member>write</member></PolicyNames><IsTruncated>false</IsTruncated></ListUserPoliciesResult></ListUserPoliciesResponse>`))
+		case "ListUserTags":
+			_, _ = w.Write([]byte(`<ListUserTagsResponse><ListUserTagsResult><Tags><member><Key>team</Key><Value>platform</Value></member></Tags><IsTruncated>false</IsTruncated></ListUserTagsResult></ListUserTagsResponse>`))
+		default:
+			t.Fatalf("unexpected action: %s", form.Get("Action"))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+	listed, err := client.ListUsers(ctx, "/team/")
+	if err != nil {
+		t.Fatalf("list users: %v", err)
+	}
+	names := make([]string, 0, len(listed))
+	for _, user := range listed {
+		names = append(names, user.UserName)
+	}
+	if !reflect.DeepEqual(names, users) {
+		t.Fatalf("unexpected users: %v", names)
+	}
+	if listed[4].UserID != "id-erin" || listed[4].Path != "/team/" {
+		t.Fatalf("unexpected user fields: %+v", listed[4])
+	}
+	if want := []string{"", "page-2", "page-4"}; !reflect.DeepEqual(markers, want) {
+		t.Fatalf("unexpected markers: %v", markers)
+	}
+
+	policies, err := client.ListUserPolicies(ctx, "alice")
+	if err != nil {
+		t.Fatalf("list user policies: %v", err)
+	}
+	if !reflect.DeepEqual(policies, []string{"read", "write"}) {
+		t.Fatalf("unexpected inline policies: %v", policies)
+	}
+
+	tags, err := client.ListUserTags(ctx, "alice")
+	if err != nil {
+		t.Fatalf("list user tags: %v", err)
+	}
+	if !reflect.DeepEqual(tags, map[string]string{"team": "platform"}) {
+		t.Fatalf("unexpected user tags: %v", tags)
+	}
+}
+
+func TestIAMErrorDetailNotImplemented(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	_, err = client.CreateGroup(context.Background(), "readers", "/")
+	if !isNotImplementedError(err) {
+		t.Fatalf("expected not implemented error, got: %v", err)
+	}
+	if detail := iamErrorDetail(err); !strings.Contains(detail, "does not implement this IAM action") {
+		t.Fatalf("unexpected error detail: %s", detail)
+	}
+
+	other := iamError{Code: "NotImplemented", Message: "CreateGroup is not supported"}
+	if !isNotImplementedError(other) {
+		t.Fatal("expected NotImplemented code to be detected")
+	}
+	if detail := iamErrorDetail(iamError{Code: "ServiceFailure", Message: "boom"}); detail != "ServiceFailure: boom" {
+		t.Fatalf("unexpected detail for other errors: %s", detail)
+	}
+}
+
+func TestIAMClientManagedPolicies(t *testing.T) {
+	t.Parallel()
+
+	type storedVersion struct {
+		id       string
+		document string
+	}
+	const policyArn = "arn:aws:iam::000000000000:policy/readers"
+	var (
+		exists         bool
+		versions       []storedVersion
+		defaultVersion string
+		nextVersion    int
+		attached       = map[string][]string{}
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse form body: %v", err)
+		}
+
+		fail := func(status int, code string) {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>` + code + `</Code><Message>` + code + `</Message></Error></ErrorResponse>`))
+		}
+		addVersion := func(document string) string {
+			nextVersion++
+			id := fmt.Sprintf("v%d", nextVersion)
+			versions = append(versions, storedVersion{id: id, document: document})
+			defaultVersion = id
+			return id
+		}
+		if action := form.Get("Action"); action != "CreatePolicy" && !strings.Contains(action, "UserPolic") && (!exists || (form.Get("PolicyArn") != "" && form.Get("PolicyArn") != policyArn)) {
+			fail(http.StatusNotFound, "NoSuchEntity")
+			return
+		}
+
+		switch form.Get("Action") {
+		case "CreatePolicy":
+			exists = true
+			addVersion(form.Get("PolicyDocument"))
+			_, _ = w.Write([]byte(`<CreatePolicyResponse><CreatePolicyResult><Policy><PolicyName>` + form.Get("PolicyName") + `</PolicyName><PolicyId>pid-1</PolicyId><Arn>` + policyArn + `</Arn><Path>/</Path><DefaultVersionId>v1</DefaultVersionId></Policy></CreatePolicyResult></CreatePolicyResponse>`))
+		case "GetPolicy":
+			_, _ = w.Write([]byte(`<GetPolicyResponse><GetPolicyResult><Policy><PolicyName>readers</PolicyName><Arn>` + policyArn + `</Arn><DefaultVersionId>` + defaultVersion + `</DefaultVersionId></Policy></GetPolicyResult></GetPolicyResponse>`))
+		case "GetPolicyVersion":
+			for _, version := range versions {
+				if version.id == form.Get("VersionId") {
+					_, _ = w.Write([]byte(`<GetPolicyVersionResponse><GetPolicyVersionResult><PolicyVersion><VersionId>` + version.id + `</VersionId><Document>` + url.QueryEscape(version.document) + `</Document></PolicyVersion></GetPolicyVersionResult></GetPolicyVersionResponse>`))
+					return
+				}
+			}
+			fail(http.StatusNotFound, "NoSuchEntity")
+		case "CreatePolicyVersion":
+			if len(versions) >= maxPolicyVersions {
+				fail(http.StatusConflict, "LimitExceeded")
+				return
+			}
+			id := addVersion(form.Get("PolicyDocument"))
+			_, _ = w.Write([]byte(`<CreatePolicyVersionResponse><CreatePolicyVersionResult><PolicyVersion><VersionId>` + id + `</VersionId><IsDefaultVersion>true</IsDefaultVersion></PolicyVersion></CreatePolicyVersionResult></CreatePolicyVersionResponse>`))
+		case "ListPolicyVersions":
+			members := ""
+			for _, version := range versions {
+				members += fmt.Sprintf(`<member><VersionId>%s</VersionId><IsDefaultVersion>%t</IsDefaultVersion></member>`, version.id, version.id == defaultVersion)
+			}
+			_, _ = w.Write([]byte(`<ListPolicyVersionsResponse><ListPolicyVersionsResult><Versions>` + members + `</Versions></ListPolicyVersionsResult></ListPolicyVersionsResponse>`))
+		case "DeletePolicyVersion":
+			if form.Get("VersionId") == defaultVersion {
+				fail(http.StatusConflict, "DeleteConflict")
+				return
+			}
+			for i, version := range versions {
+				if version.id == form.Get("VersionId") {
+					versions = append(versions[:i], versions[i+1:]...)
+					_, _ = w.Write([]byte(`<DeletePolicyVersionResponse/>`))
+					return
+				}
+			}
+			fail(http.StatusNotFound, "NoSuchEntity")
+		case "DeletePolicy":
+			if len(versions) > 1 {
+				fail(http.StatusConflict, "DeleteConflict")
+				return
+			}
+			exists = false
+			versions = nil
+			_, _ = w.Write([]byte(`<DeletePolicyResponse/>`))
+		case "AttachUserPolicy":
+			attached[form.Get("UserName")] = append(attached[form.Get("UserName")], form.Get("PolicyArn"))
+			_, _ = w.Write([]byte(`<AttachUserPolicyResponse/>`))
+		case "DetachUserPolicy":
+			user := form.Get("UserName")
+			for i, arn := range attached[user] {
+				if arn == form.Get("PolicyArn") {
+					attached[user] = append(attached[user][:i], attached[user][i+1:]...)
+					_, _ = w.Write([]byte(`<DetachUserPolicyResponse/>`))
+					return
+				}
+			}
+			fail(http.StatusNotFound, "NoSuchEntity")
+		case "ListAttachedUserPolicies":
+			members := ""
+			for _, arn := range attached[form.Get("UserName")] {
+				members += `<member><PolicyName>readers</PolicyName><PolicyArn>` + arn + `</PolicyArn></member>`
+			}
+			_, _ = w.Write([]byte(`<ListAttachedUserPoliciesResponse><ListAttachedUserPoliciesResult><AttachedPolicies>` + members + `</AttachedPolicies></ListAttachedUserPoliciesResult></ListAttachedUserPoliciesResponse>`))
+		default:
+			t.Fatalf("unexpected action: %s", form.Get("Action"))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	policy, err := client.CreatePolicy(ctx, "readers", "/", "", `{"Version":"2012-10-17","Statement":[]}`)
+	if err != nil {
+		t.Fatalf("create policy: %v", err)
+	}
+	if policy.Arn != policyArn || policy.DefaultVersionID != "v1" {
+		t.Fatalf("unexpected policy: %+v", policy)
+	}
+
+	// Eight updates must keep working past the five-version limit.
+	var document string
+	for i := 0; i < 8; i++ {
+		document = fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Sid":"S%d","Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`, i)
+		version, err := client.UpdatePolicyDocument(ctx, policyArn, document)
+		if err != nil {
+			t.Fatalf("update policy document %d: %v", i, err)
+		}
+		if version.VersionID != fmt.Sprintf("v%d", i+2) {
+			t.Fatalf("unexpected version after update %d: %+v", i, version)
+		}
+	}
+
+	listed, err := client.ListPolicyVersions(ctx, policyArn)
+	if err != nil {
+		t.Fatalf("list policy versions: %v", err)
+	}
+	if len(listed) != maxPolicyVersions {
+		t.Fatalf("expected %d versions, got %d", maxPolicyVersions, len(listed))
+	}
+	if listed[0].VersionID != "v5" {
+		t.Fatalf("expected oldest versions to be pruned first, got: %+v", listed)
+	}
+
+	policy, err = client.GetPolicy(ctx, policyArn)
+	if err != nil {
+		t.Fatalf("get policy: %v", err)
+	}
+	version, err := client.GetPolicyVersion(ctx, policyArn, policy.DefaultVersionID)
+	if err != nil {
+		t.Fatalf("get policy version: %v", err)
+	}
+	if version.Document != document {
+		t.Fatalf("unexpected default document: %s", version.Document)
+	}
+
+	if err := client.AttachUserPolicy(ctx, "alice", policyArn); err != nil {
+		t.Fatalf("attach user policy: %v", err)
+	}
+	attachedPolicies, err := client.ListAttachedUserPolicies(ctx, "alice")
+	if err != nil {
+		t.Fatalf("list attached user policies: %v", err)
+	}
+	if len(attachedPolicies) != 1 || attachedPolicies[0].PolicyArn != policyArn {
+		t.Fatalf("unexpected attached policies: %+v", attachedPolicies)
+	}
+	if err := client.DetachUserPolicy(ctx, "alice", policyArn); err != nil {
+		t.Fatalf("detach user policy: %v", err)
+	}
+	if err := client.DetachUserPolicy(ctx, "alice", policyArn); !isNoSuchEntityError(err) {
+		t.Fatalf("expected NoSuchEntity on second detach, got: %v", err)
+	}
+
+	if err := client.DeletePolicy(ctx, policyArn); err != nil {
+		t.Fatalf("delete policy: %v", err)
+	}
+	if _, err := client.GetPolicy(ctx, policyArn); !isNoSuchEntityError(err) {
+		t.Fatalf("expected NoSuchEntity after delete, got: %v", err)
+	}
+}
+
+func TestIAMClientGetPolicyByName(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse form body: %v", err)
+		}
+
+		switch form.Get("Action") {
+		case "ListPolicies":
+			if form.Get("Scope") != "Local" || form.Get("PathPrefix") != "/team/" {
+				t.Fatalf("unexpected list policies request: %v", form)
+			}
+			// Return the wanted policy on the second page.
+			if form.Get("Marker") == "" {
+				_, _ = w.Write([]byte(`<ListPoliciesResponse><ListPoliciesResult><Policies><member><PolicyName>writers</PolicyName><Arn>arn:aws:iam::000000000000:policy/team/writers</Arn></member></Policies><IsTruncated>true</IsTruncated><Marker>page-2</Marker></ListPoliciesResult></ListPoliciesResponse>`))
+				return
+			}
+			_, _ = w.Write([]byte(`<ListPoliciesResponse><ListPoliciesResult><Policies><member><PolicyName>readers</PolicyName><Arn>arn:aws:iam::000000000000:policy/team/readers</Arn></member></Policies></ListPoliciesResult></ListPoliciesResponse>`))
+		case "GetPolicy":
+			if form.Get("PolicyArn") != "arn:aws:iam::000000000000:policy/team/readers" {
+				t.Fatalf("unexpected policy arn: %s", form.Get("PolicyArn"))
+			}
+			_, _ = w.Write([]byte(`<GetPolicyResponse><GetPolicyResult><Policy><PolicyName>readers</PolicyName><PolicyId>pid-2</PolicyId><Arn>arn:aws:iam::000000000000:policy/team/readers</Arn><Path>/team/</Path><DefaultVersionId>v1</DefaultVersionId></Policy></GetPolicyResult></GetPolicyResponse>`))
+		default:
+			t.Fatalf("unexpected action: %s", form.Get("Action"))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	policy, err := client.GetPolicyByName(ctx, "readers", "/team/")
+	if err != nil {
+		t.Fatalf("get policy by name: %v", err)
+	}
+	if policy.Arn != "arn:aws:iam::000000000000:policy/team/readers" || policy.PolicyID != "pid-2" || policy.DefaultVersionID != "v1" {
+		t.Fatalf("unexpected policy: %+v", policy)
+	}
+
+	if _, err := client.GetPolicyByName(ctx, "missing", "/team/"); !isNoSuchEntityError(err) {
+		t.Fatalf("expected NoSuchEntity for missing policy, got: %v", err)
+	}
+}
+
+func TestPolicyVersionOlder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    iamPolicyVersion
+		b    iamPolicyVersion
+		want bool
+	}{
+		{
+			name: "create date",
+			a:    iamPolicyVersion{VersionID: "v9", CreateDate: "2026-01-01T00:00:00Z"},
+			b:    iamPolicyVersion{VersionID: "v2", CreateDate: "2026-01-02T00:00:00Z"},
+			want: true,
+		},
+		{
+			name: "numeric version id",
+			a:    iamPolicyVersion{VersionID: "v2"},
+			b:    iamPolicyVersion{VersionID: "v10"},
+			want: true,
+		},
+		{
+			name: "newer version id",
+			a:    iamPolicyVersion{VersionID: "v10"},
+			b:    iamPolicyVersion{VersionID: "v9"},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := policyVersionOlder(tt.a, tt.b); got != tt.want {
+				t.Fatalf("policyVersionOlder(%s, %s) = %t, want %t", tt.a.VersionID, tt.b.VersionID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIAMClientRoles(t *testing.T) {
+	t.Parallel()
+
+	type storedRole struct {
+		path        string
+		description string
+		trust       string
+	}
+	roles := map[string]*storedRole{}
+	rolePolicies := map[string]string{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse form body: %v", err)
+		}
+
+		name := form.Get("RoleName")
+		notFound := func() {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>NoSuchEntity</Code><Message>Not found</Message></Error></ErrorResponse>`))
+		}
+		roleXML := func(role *storedRole) string {
+			return `<Role><Path>` + role.path + `</Path><RoleName>` + name + `</RoleName><RoleId>rid-1</RoleId><Arn>arn:aws:iam::000000000000:role/` + name + `</Arn><Description>` + role.description + `</Description><AssumeRolePolicyDocument>` + url.QueryEscape(role.trust) + `</AssumeRolePolicyDocument></Role>`
+		}
+
+		switch form.Get("Action") {
+		case "CreateRole":
+			roles[name] = &storedRole{path: form.Get("Path"), description: form.Get("Description"), trust: form.Get("AssumeRolePolicyDocument")}
+			_, _ = w.Write([]byte(`<CreateRoleResponse><CreateRoleResult>` + roleXML(roles[name]) + `</CreateRoleResult></CreateRoleResponse>`))
+		case "GetRole":
+			role, ok := roles[name]
+			if !ok {
+				notFound()
+				return
+			}
+			_, _ = w.Write([]byte(`<GetRoleResponse><GetRoleResult>` + roleXML(role) + `</GetRoleResult></GetRoleResponse>`))
+		case "UpdateAssumeRolePolicy":
+			role, ok := roles[name]
+			if !ok {
+				notFound()
+				return
+			}
+			role.trust = form.Get("PolicyDocument")
+			_, _ = w.Write([]byte(`<UpdateAssumeRolePolicyResponse/>`))
+		case "DeleteRole":
+			if _, ok := roles[name]; !ok {
+				notFound()
+				return
+			}
+			delete(roles, name)
+			_, _ = w.Write([]byte(`<DeleteRoleResponse/>`))
+		case "PutRolePolicy":
+			rolePolicies[name+":"+form.Get("PolicyName")] = form.Get("PolicyDocument")
+			_, _ = w.Write([]byte(`<PutRolePolicyResponse/>`))
+		case "GetRolePolicy":
+			policy, ok := rolePolicies[name+":"+form.Get("PolicyName")]
+			if !ok {
+				notFound()
+				return
+			}
+			_, _ = w.Write([]byte(`<GetRolePolicyResponse><GetRolePolicyResult><RoleName>` + name + `</RoleName><PolicyName>` + form.Get("PolicyName") + `</PolicyName><PolicyDocument>` + url.QueryEscape(policy) + `</PolicyDocument></GetRolePolicyResult></GetRolePolicyResponse>`))
+		case "DeleteRolePolicy":
+			delete(rolePolicies, name+":"+form.Get("PolicyName"))
+			_, _ = w.Write([]byte(`<DeleteRolePolicyResponse/>`))
+		default:
+			t.Fatalf("unexpected action: %s", form.Get("Action"))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	trust := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"*"},"Action":"sts:AssumeRole"}]}`
+	role, err := client.CreateRole(ctx, "terraform", "/ci/", "CI admin", trust)
+	if err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	if role.Arn != "arn:aws:iam::000000000000:role/terraform" || role.RoleID != "rid-1" {
+		t.Fatalf("unexpected role: %+v", role)
+	}
+
+	got, err := client.GetRole(ctx, "terraform")
+	if err != nil {
+		t.Fatalf("get role: %v", err)
+	}
+	if got.Path != "/ci/" || got.Description != "CI admin" || got.AssumeRolePolicyDocument != trust {
+		t.Fatalf("unexpected role: %+v", got)
+	}
+
+	updatedTrust := `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Principal":{"AWS":"*"},"Action":"sts:AssumeRole"}]}`
+	if err := client.UpdateAssumeRolePolicy(ctx, "terraform", updatedTrust); err != nil {
+		t.Fatalf("update assume role policy: %v", err)
+	}
+	if got, err := client.GetRole(ctx, "terraform"); err != nil || got.AssumeRolePolicyDocument != updatedTrust {
+		t.Fatalf("unexpected trust policy after update: %+v, %v", got, err)
+	}
+
+	policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"iam:*","Resource":"*"}]}`
+	if err := client.PutRolePolicy(ctx, "terraform", "admin", policy); err != nil {
+		t.Fatalf("put role policy: %v", err)
+	}
+	gotPolicy, err := client.GetRolePolicy(ctx, "terraform", "admin")
+	if err != nil {
+		t.Fatalf("get role policy: %v", err)
+	}
+	if gotPolicy != policy {
+		t.Fatalf("unexpected role policy: %s", gotPolicy)
+	}
+	if err := client.DeleteRolePolicy(ctx, "terraform", "admin"); err != nil {
+		t.Fatalf("delete role policy: %v", err)
+	}
+	if _, err := client.GetRolePolicy(ctx, "terraform", "admin"); !isNoSuchEntityError(err) {
+		t.Fatalf("expected NoSuchEntity after policy delete, got: %v", err)
+	}
+
+	if err := client.DeleteRole(ctx, "terraform"); err != nil {
+		t.Fatalf("delete role: %v", err)
+	}
+	if _, err := client.GetRole(ctx, "terraform"); !isNoSuchEntityError(err) {
+		t.Fatalf("expected NoSuchEntity after role delete, got: %v", err)
+	}
+}
+
+func TestIAMClientAssumeRole(t *testing.T) {
+	t.Parallel()
+
+	var assumeCalls int
+	var signedWith []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			t.Fatalf("parse form body: %v", err)
+		}
+
+		auth := r.Header.Get("Authorization")
+		switch form.Get("Action") {
+		case "AssumeRole":
+			if !strings.Contains(auth, "Credential=test-key/") || !strings.Contains(auth, "/sts/aws4_request") {
+				t.Fatalf("AssumeRole must be signed with the static key for sts, got: %s", auth)
+			}
+			if form.Get("RoleArn") != "arn:aws:iam::000000000000:role/terraform" || form.Get("RoleSessionName") != "ci" || form.Get("DurationSeconds") != "900" {
+				t.Fatalf("unexpected AssumeRole form: %v", form)
+			}
+			assumeCalls++
+			// The first credentials are already expired so the next request
+			// has to refresh them; the second set is valid for an hour.
+			expiration := time.Now().Add(-time.Minute)
+			if assumeCalls > 1 {
+				expiration = time.Now().Add(time.Hour)
+			}
+			_, _ = fmt.Fprintf(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials><AccessKeyId>ASIATEMP%d</AccessKeyId><SecretAccessKey>temp-secret</SecretAccessKey><SessionToken>token-%d</SessionToken><Expiration>%s</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>`,
+				assumeCalls, assumeCalls, expiration.UTC().Format(time.RFC3339))
+		case "GetUser":
+			signedWith = append(signedWith, r.Header.Get("X-Amz-Security-Token"))
+			if !strings.Contains(auth, fmt.Sprintf("Credential=ASIATEMP%d/", assumeCalls)) {
+				t.Fatalf("expected request signed with temporary key %d, got: %s", assumeCalls, auth)
+			}
+			_, _ = w.Write([]byte(`<GetUserResponse><GetUserResult><User><UserName>alice</UserName></User></GetUserResult></GetUserResponse>`))
+		default:
+			t.Fatalf("unexpected action: %s", form.Get("Action"))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+		AssumeRole: &assumeRoleConfig{
+			RoleARN:     "arn:aws:iam::000000000000:role/terraform",
+			SessionName: "ci",
+			Duration:    15 * time.Minute,
+		},
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetUser(ctx, "alice"); err != nil {
+			t.Fatalf("get user %d: %v", i, err)
+		}
+	}
+
+	if assumeCalls != 2 {
+		t.Fatalf("expected expired credentials to be refreshed once and then cached, got %d AssumeRole calls", assumeCalls)
+	}
+	if !reflect.DeepEqual(signedWith, []string{"token-1", "token-2", "token-2"}) {
+		t.Fatalf("unexpected session tokens: %v", signedWith)
+	}
+
+	if _, err := newIAMClient(iamClientConfig{
+		Endpoint:   srv.URL,
+		AccessKey:  "test-key",
+		SecretKey:  "test-secret",
+		AssumeRole: &assumeRoleConfig{},
+	}); err == nil {
+		t.Fatal("expected an error for assume_role without role_arn")
+	}
+}
+
+func TestIAMClientListBuckets(t *testing.T) {
+	t.Parallel()
+
+	var requests []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL)
+		}
+		requests = append(requests, r.URL.Query())
+
+		// The second page ignores the prefix, as older SeaweedFS versions do.
+		w.Header().Set("Content-Type", "application/xml")
+		if r.URL.Query().Get("continuation-token") == "" {
+			_, _ = w.Write([]byte(`<ListAllMyBucketsResult><Buckets><Bucket><Name>team-b</Name><CreationDate>2024-02-01T00:00:00Z</CreationDate></Bucket></Buckets><ContinuationToken>page-2</ContinuationToken></ListAllMyBucketsResult>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<ListAllMyBucketsResult><Buckets><Bucket><Name>other</Name><CreationDate>2024-03-01T00:00:00Z</CreationDate></Bucket><Bucket><Name>team-a</Name><CreationDate>2024-01-01T00:00:00Z</CreationDate></Bucket></Buckets></ListAllMyBucketsResult>`))
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	buckets, err := client.ListBuckets(context.Background(), "team-")
+	if err != nil {
+		t.Fatalf("list buckets: %v", err)
+	}
+	want := []s3BucketInfo{
+		{Name: "team-a", CreationDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "team-b", CreationDate: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(buckets, want) {
+		t.Fatalf("unexpected buckets:\n got: %+v\nwant: %+v", buckets, want)
+	}
+	if len(requests) != 2 || requests[0].Get("prefix") != "team-" || requests[1].Get("continuation-token") != "page-2" {
+		t.Fatalf("unexpected list requests: %v", requests)
+	}
+}
+
+func TestIAMClientBucketVersioning(t *testing.T) {
+	t.Parallel()
+
+	status := ""
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["versioning"]; !ok {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL)
+		}
+		if r.URL.Path != "/b1" {
+			t.Fatalf("unexpected bucket path: %s", r.URL.Path)
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/xml")
+			if status == "" {
+				_, _ = w.Write([]byte(`<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"/>`))
+				return
+			}
+			_, _ = w.Write([]byte(`<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Status>` + status + `</Status></VersioningConfiguration>`))
+		case http.MethodPut:
+			var in struct {
+				Status string `xml:"Status"`
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("read versioning body: %v", err)
+			}
+			if err := xml.Unmarshal(body, &in); err != nil {
+				t.Fatalf("unmarshal versioning body: %v", err)
+			}
+			status = in.Status
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Fatalf("unexpected versioning method: %s", r.Method)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	got, err := client.GetBucketVersioning(ctx, "b1")
+	if err != nil {
+		t.Fatalf("get bucket versioning: %v", err)
+	}
+	if got != "" {
+		t.Fatalf("expected never-enabled versioning, got %q", got)
+	}
+
+	for _, want := range []string{"Enabled", "Suspended"} {
+		if err := client.PutBucketVersioning(ctx, "b1", want); err != nil {
+			t.Fatalf("put bucket versioning %s: %v", want, err)
+		}
+		got, err = client.GetBucketVersioning(ctx, "b1")
+		if err != nil {
+			t.Fatalf("get bucket versioning: %v", err)
+		}
+		if got != want {
+			t.Fatalf("expected versioning %q, got %q", want, got)
+		}
+	}
+}
+
+func TestIAMClientBucketVersioningNotImplemented(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusNotImplemented)
+		_, _ = w.Write([]byte(`<Error><Code>NotImplemented</Code><Message>A header you provided implies functionality that is not implemented</Message></Error>`))
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	if _, err := client.GetBucketVersioning(context.Background(), "b1"); !isNotImplementedError(err) {
+		t.Fatalf("expected not implemented error, got: %v", err)
+	}
+}
+
+func TestIAMClientBucketLifecycle(t *testing.T) {
+	t.Parallel()
+
+	var lifecycle []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["lifecycle"]; !ok {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL)
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			if lifecycle == nil {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`<Error><Code>NoSuchLifecycleConfiguration</Code><Message>The lifecycle configuration does not exist</Message></Error>`))
+				return
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = w.Write(lifecycle)
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("read lifecycle body: %v", err)
+			}
+			lifecycle = body
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			lifecycle = nil
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected lifecycle method: %s", r.Method)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	rules, err := client.GetBucketLifecycleRules(ctx, "b1")
+	if err != nil {
+		t.Fatalf("get empty lifecycle: %v", err)
+	}
+	if len(rules) != 0 {
+		t.Fatalf("expected no rules, got: %+v", rules)
+	}
+
+	want := []bucketLifecycleRule{
+		{ID: "tmp", Status: "Enabled", Prefix: "tmp/", Tags: map[string]string{}, ExpirationDays: 7},
+		{ID: "tagged", Status: "Enabled", Tags: map[string]string{"scratch": "true"}, ExpirationDays: 1},
+		{ID: "mixed", Status: "Disabled", Prefix: "logs/", Tags: map[string]string{"a": "1", "b": "2"}, NoncurrentVersionExpirationDays: 30, AbortIncompleteMultipartUploadDays: 2},
+	}
+	if err := client.PutBucketLifecycleRules(ctx, "b1", want); err != nil {
+		t.Fatalf("put lifecycle: %v", err)
+	}
+
+	rules, err = client.GetBucketLifecycleRules(ctx, "b1")
+	if err != nil {
+		t.Fatalf("get lifecycle: %v", err)
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("unexpected lifecycle rules:\n got: %+v\nwant: %+v", rules, want)
+	}
+
+	if err := client.DeleteBucketLifecycle(ctx, "b1"); err != nil {
+		t.Fatalf("delete lifecycle: %v", err)
+	}
+	rules, err = client.GetBucketLifecycleRules(ctx, "b1")
+	if err != nil {
+		t.Fatalf("get lifecycle after delete: %v", err)
+	}
+	if len(rules) != 0 {
+		t.Fatalf("expected no rules after delete, got: %+v", rules)
+	}
+}
+
+func TestIAMClientBucketPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := ""
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["policy"]; !ok {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL)
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			if policy == "" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`<Error><Code>NoSuchBucketPolicy</Code><Message>The bucket policy does not exist</Message></Error>`))
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(policy))
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("read policy body: %v", err)
+			}
+			policy = string(body)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			policy = ""
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected policy method: %s", r.Method)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	got, err := client.GetBucketPolicy(ctx, "b1")
+	if err != nil {
+		t.Fatalf("get missing bucket policy: %v", err)
+	}
+	if got != "" {
+		t.Fatalf("expected empty policy, got: %s", got)
+	}
+
+	public := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::b1/*"]}]}`
+	if err := client.PutBucketPolicy(ctx, "b1", public); err != nil {
+		t.Fatalf("put bucket policy: %v", err)
+	}
+	got, err = client.GetBucketPolicy(ctx, "b1")
+	if err != nil {
+		t.Fatalf("get bucket policy: %v", err)
+	}
+	if !policiesSemanticallyEqual(got, public) {
+		t.Fatalf("unexpected bucket policy: %s", got)
+	}
+
+	if err := client.DeleteBucketPolicy(ctx, "b1"); err != nil {
+		t.Fatalf("delete bucket policy: %v", err)
+	}
+	got, err = client.GetBucketPolicy(ctx, "b1")
+	if err != nil {
+		t.Fatalf("get bucket policy after delete: %v", err)
+	}
+	if got != "" {
+		t.Fatalf("expected empty policy after delete, got: %s", got)
+	}
+}
+
+func TestIAMClientBucketCORS(t *testing.T) {
+	t.Parallel()
+
+	var cors []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["cors"]; !ok {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL)
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			if cors == nil {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`<Error><Code>NoSuchCORSConfiguration</Code><Message>The CORS configuration does not exist</Message></Error>`))
+				return
+			}
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = w.Write(cors)
+		case http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("read cors body: %v", err)
+			}
+			cors = body
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			cors = nil
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected cors method: %s", r.Method)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	rules, err := client.GetBucketCORSRules(ctx, "b1")
+	if err != nil {
+		t.Fatalf("get empty cors: %v", err)
+	}
+	if len(rules) != 0 {
+		t.Fatalf("expected no cors rules, got: %+v", rules)
+	}
+
+	want := []bucketCORSRule{
+		{
+			ID:             "uploads",
+			AllowedHeaders: []string{"*"},
+			AllowedMethods: []string{"PUT", "POST"},
+			AllowedOrigins: []string{"https://app.example.com"},
+			ExposeHeaders:  []string{"ETag"},
+			MaxAgeSeconds:  3000,
+		},
+		{
+			AllowedMethods: []string{"GET"},
+			AllowedOrigins: []string{"*"},
+		},
+	}
+	if err := client.PutBucketCORSRules(ctx, "b1", want); err != nil {
+		t.Fatalf("put cors: %v", err)
+	}
+
+	rules, err = client.GetBucketCORSRules(ctx, "b1")
+	if err != nil {
+		t.Fatalf("get cors: %v", err)
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Fatalf("unexpected cors rules:\n got: %+v\nwant: %+v", rules, want)
+	}
+
+	if err := client.DeleteBucketCORS(ctx, "b1"); err != nil {
+		t.Fatalf("delete cors: %v", err)
+	}
+	rules, err = client.GetBucketCORSRules(ctx, "b1")
+	if err != nil {
+		t.Fatalf("get cors after delete: %v", err)
+	}
+	if len(rules) != 0 {
+		t.Fatalf("expected no cors rules after delete, got: %+v", rules)
+	}
+}
+
+func TestIAMClientEmptyBucket(t *testing.T) {
+	t.Parallel()
+
+	type objectVersion struct {
+		key       string
+		versionID string
+		marker    bool
+	}
+
+	var mu sync.Mutex
+	objects := map[string]map[string]bool{"plain": {}, "versioned": {}}
+	versions := map[string][]objectVersion{}
+	uploads := map[string]map[string]string{"plain": {"big.bin": "upload-1"}, "versioned": {}}
+	versioning := map[string]string{"plain": "", "versioned": "Enabled"}
+
+	for i := range 2500 {
+		objects["plain"][fmt.Sprintf("obj-%05d", i)] = true
+	}
+	for i := range 3 {
+		key := fmt.Sprintf("doc-%d", i)
+		versions["versioned"] = append(versions["versioned"],
+			objectVersion{key: key, versionID: "v1"},
+			objectVersion{key: key, versionID: "v2"},
+			objectVersion{key: key, versionID: "dm", marker: true},
+		)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		query := r.URL.Query()
+		w.Header().Set("Content-Type", "application/xml")
+
+		switch {
+		case r.Method == http.MethodGet && query.Has("uploads"):
+			var b strings.Builder
+			b.WriteString(`<ListMultipartUploadsResult><Bucket>` + bucket + `</Bucket><IsTruncated>false</IsTruncated>`)
+			for uploadKey, uploadID := range uploads[bucket] {
+				b.WriteString(`<Upload><Key>` + uploadKey + `</Key><UploadId>` + uploadID + `</UploadId></Upload>`)
+			}
+			b.WriteString(`</ListMultipartUploadsResult>`)
+			_, _ = w.Write([]byte(b.String()))
+		case r.Method == http.MethodDelete && query.Has("uploadId"):
+			if uploads[bucket][key] != query.Get("uploadId") {
+				t.Errorf("unexpected abort of %s/%s", bucket, key)
+			}
+			delete(uploads[bucket], key)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && query.Has("versioning"):
+			_, _ = w.Write([]byte(`<VersioningConfiguration><Status>` + versioning[bucket] + `</Status></VersioningConfiguration>`))
+		case r.Method == http.MethodGet && query.Get("list-type") == "2":
+			keys := make([]string, 0, len(objects[bucket]))
+			for k := range objects[bucket] {
+				if k > query.Get("continuation-token") {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			truncated := len(keys) > 1000
+			if truncated {
+				keys = keys[:1000]
+			}
+			var b strings.Builder
+			b.WriteString(`<ListBucketResult><Name>` + bucket + `</Name>`)
+			for _, k := range keys {
+				b.WriteString(`<Contents><Key>` + k + `</Key></Contents>`)
+			}
+			if truncated {
+				b.WriteString(`<IsTruncated>true</IsTruncated><NextContinuationToken>` + keys[len(keys)-1] + `</NextContinuationToken>`)
+			} else {
+				b.WriteString(`<IsTruncated>false</IsTruncated>`)
+			}
+			b.WriteString(`</ListBucketResult>`)
+			_, _ = w.Write([]byte(b.String()))
+		case r.Method == http.MethodGet && query.Has("versions"):
+			var b strings.Builder
+			b.WriteString(`<ListVersionsResult><Name>` + bucket + `</Name><IsTruncated>false</IsTruncated>`)
+			for _, v := range versions[bucket] {
+				element := "Version"
+				if v.marker {
+					element = "DeleteMarker"
+				}
+				b.WriteString(`<` + element + `><Key>` + v.key + `</Key><VersionId>` + v.versionID + `</VersionId></` + element + `>`)
+			}
+			b.WriteString(`</ListVersionsResult>`)
+			_, _ = w.Write([]byte(b.String()))
+		case r.Method == http.MethodPost && query.Has("delete"):
+			var in struct {
+				Objects []struct {
+					Key       string `xml:"Key"`
+					VersionID string `xml:"VersionId"`
+				} `xml:"Object"`
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("read delete body: %v", err)
+			}
+			if err := xml.Unmarshal(body, &in); err != nil {
+				t.Errorf("unmarshal delete body: %v", err)
+			}
+			if len(in.Objects) > 1000 {
+				t.Errorf("delete batch too large: %d", len(in.Objects))
+			}
+			for _, obj := range in.Objects {
+				if obj.VersionID == "" {
+					delete(objects[bucket], obj.Key)
+					continue
+				}
+				kept := versions[bucket][:0]
+				for _, v := range versions[bucket] {
+					if v.key != obj.Key || v.versionID != obj.VersionID {
+						kept = append(kept, v)
+					}
+				}
+				versions[bucket] = kept
+			}
+			_, _ = w.Write([]byte(`<DeleteResult></DeleteResult>`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if err := client.EmptyBucket(ctx, "plain"); err != nil {
+		t.Fatalf("empty plain bucket: %v", err)
+	}
+	if err := client.EmptyBucket(ctx, "versioned"); err != nil {
+		t.Fatalf("empty versioned bucket: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(objects["plain"]) != 0 {
+		t.Fatalf("expected plain bucket to be empty, %d objects left", len(objects["plain"]))
+	}
+	if len(uploads["plain"]) != 0 {
+		t.Fatalf("expected multipart uploads to be aborted, got: %+v", uploads["plain"])
+	}
+	if len(versions["versioned"]) != 0 {
+		t.Fatalf("expected versioned bucket to be empty, got: %+v", versions["versioned"])
+	}
+}
+
+func TestIAMClientObjectLifecycle(t *testing.T) {
+	t.Parallel()
+
+	type storedObject struct {
+		body        []byte
+		etag        string
+		contentType string
+		metadata    map[string]string
+	}
+	type pendingUpload struct {
+		contentType string
+		metadata    map[string]string
+		parts       map[int][]byte
+	}
+
+	var mu sync.Mutex
+	objects := map[string]storedObject{}
+	multipart := map[string]*pendingUpload{}
+
+	userMetadata := func(h http.Header) map[string]string {
+		out := map[string]string{}
+		for name, values := range h {
+			if rest, ok := strings.CutPrefix(strings.ToLower(name), "x-amz-meta-"); ok {
+				out[rest] = values[0]
+			}
+		}
+		return out
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		query := r.URL.Query()
+		path := r.URL.Path
+
+		switch {
+		case r.Method == http.MethodPost && query.Has("uploads"):
+			multipart["upload-1"] = &pendingUpload{
+				contentType: r.Header.Get("Content-Type"),
+				metadata:    userMetadata(r.Header),
+				parts:       map[int][]byte{},
+			}
+			_, _ = w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`))
+		case r.Method == http.MethodPut && query.Has("uploadId"):
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("read part body: %v", err)
+			}
+			var partNumber int
+			_, _ = fmt.Sscan(query.Get("partNumber"), &partNumber)
+			multipart[query.Get("uploadId")].parts[partNumber] = body
+			sum := md5.Sum(body)
+			w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		case r.Method == http.MethodPost && query.Has("uploadId"):
+			upload := multipart[query.Get("uploadId")]
+			var body, partSums []byte
+			for i := 1; i <= len(upload.parts); i++ {
+				body = append(body, upload.parts[i]...)
+				sum := md5.Sum(upload.parts[i])
+				partSums = append(partSums, sum[:]...)
+			}
+			total := md5.Sum(partSums)
+			etag := fmt.Sprintf("%s-%d", hex.EncodeToString(total[:]), len(upload.parts))
+			objects[path] = storedObject{body: body, etag: etag, contentType: upload.contentType, metadata: upload.metadata}
+			delete(multipart, query.Get("uploadId"))
+			_, _ = w.Write([]byte(`<CompleteMultipartUploadResult><ETag>&quot;` + etag + `&quot;</ETag></CompleteMultipartUploadResult>`))
+		case r.Method == http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("read object body: %v", err)
+			}
+			sum := md5.Sum(body)
+			etag := hex.EncodeToString(sum[:])
+			objects[path] = storedObject{body: body, etag: etag, contentType: r.Header.Get("Content-Type"), metadata: userMetadata(r.Header)}
+			w.Header().Set("ETag", `"`+etag+`"`)
+		case r.Method == http.MethodHead:
+			obj, ok := objects[path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", `"`+obj.etag+`"`)
+			w.Header().Set("Content-Type", obj.contentType)
+			w.Header().Set("Content-Length", fmt.Sprint(len(obj.body)))
+			for k, v := range obj.metadata {
+				w.Header().Set("X-Amz-Meta-"+k, v)
+			}
+		case r.Method == http.MethodDelete:
+			delete(objects, path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := newIAMClient(iamClientConfig{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		AccessKey: "test-key",
+		SecretKey: "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	ctx := context.Background()
+
+	small := []byte(`{"feature":"on"}`)
+	large := bytes.Repeat([]byte("0123456789abcdef"), (s3ObjectPartSize*2+1024)/16)
+
+	for _, tc := range []struct {
+		key        string
+		body       []byte
+		etagSuffix string
+	}{
+		{key: "config/app.json", body: small},
+		{key: "blobs/large.bin", body: large, etagSuffix: "-3"},
+	} {
+		in := s3ObjectInput{
+			Bucket:      "b1",
+			Key:         tc.key,
+			ContentType: "application/json",
+			Metadata:    map[string]string{"owner": "platform"},
+		}
+		info, err := client.PutObject(ctx, in, bytes.NewReader(tc.body), int64(len(tc.body)))
+		if err != nil {
+			t.Fatalf("put object %s: %v", tc.key, err)
+		}
+
+		want, err := computeObjectETag(bytes.NewReader(tc.body), int64(len(tc.body)))
+		if err != nil {
+			t.Fatalf("compute etag: %v", err)
+		}
+		if info.ETag != want {
+			t.Fatalf("expected etag %q for %s, got %q", want, tc.key, info.ETag)
+		}
+		if !strings.HasSuffix(want, tc.etagSuffix) {
+			t.Fatalf("expected etag for %s to end with %q, got %q", tc.key, tc.etagSuffix, want)
+		}
+
+		head, err := client.HeadObject(ctx, "b1", tc.key)
+		if err != nil {
+			t.Fatalf("head object %s: %v", tc.key, err)
+		}
+		if head.ETag != want || head.ContentType != "application/json" || head.Metadata["owner"] != "platform" {
+			t.Fatalf("unexpected head object result for %s: %+v", tc.key, head)
+		}
+		if head.Size != int64(len(tc.body)) {
+			t.Fatalf("expected size %d for %s, got %d", len(tc.body), tc.key, head.Size)
+		}
+
+		if err := client.DeleteObject(ctx, "b1", tc.key); err != nil {
+			t.Fatalf("delete object %s: %v", tc.key, err)
+		}
+		_, err = client.HeadObject(ctx, "b1", tc.key)
+		if !isNoSuchKeyError(err) {
+			t.Fatalf("expected not found after delete of %s, got: %v", tc.key, err)
+		}
+	}
+}
+
+func TestPoliciesSemanticallyEqual(t *testing.T) {
+	t.Parallel()
+
+	a := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":"*"}]}`
+	b := `{
+  "Statement": [
+    {
+      "Resource": "*",
+      "Action": [
+        "s3:*"
+      ],
+      "Effect": "Allow"
+    }
+  ],
+  "Version": "2012-10-17"
+}`
+
+	if !policiesSemanticallyEqual(a, b) {
+		t.Fatalf("expected policies to be semantically equal")
+	}
+}
+
+func TestCanonicalizePolicyJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		a     string
+		b     string
+		equal bool
+	}{
+		{
+			name:  "single value versus list",
+			a:     `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3:::b/*"]}]}`,
+			b:     `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":["s3:GetObject"],"Resource":"arn:aws:s3:::b/*"}}`,
+			equal: true,
+		},
+		{
+			name:  "reordered and duplicated actions",
+			a:     `{"Statement":[{"Effect":"Allow","Action":["s3:PutObject","s3:GetObject"],"NotResource":["b","a"]}]}`,
+			b:     `{"Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject","s3:GetObject"],"NotResource":["a","b"]}]}`,
+			equal: true,
+		},
+		{
+			name:  "reordered statements",
+			a:     `{"Statement":[{"Sid":"A","Effect":"Allow","Action":"s3:GetObject","Resource":"*"},{"Sid":"B","Effect":"Deny","Action":"s3:DeleteObject","Resource":"*"}]}`,
+			b:     `{"Statement":[{"Sid":"B","Effect":"Deny","Action":"s3:DeleteObject","Resource":"*"},{"Sid":"A","Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			equal: true,
+		},
+		{
+			name:  "principal and condition values",
+			a:     `{"Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:b","arn:a"]},"Action":"s3:*","Resource":"*","Condition":{"IpAddress":{"aws:SourceIp":["10.0.0.0/8","192.168.0.0/16"]},"Bool":{"aws:SecureTransport":["true"]}}}]}`,
+			b:     `{"Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:a","arn:b"]},"Action":"s3:*","Resource":"*","Condition":{"Bool":{"aws:SecureTransport":"true"},"IpAddress":{"aws:SourceIp":["192.168.0.0/16","10.0.0.0/8"]}}}]}`,
+			equal: true,
+		},
+		{
+			name:  "different effect",
+			a:     `{"Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`,
+			b:     `{"Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"*"}]}`,
+			equal: false,
+		},
+		{
+			name:  "additional action",
+			a:     `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			b:     `{"Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"*"}]}`,
+			equal: false,
+		},
+		{
+			name:  "anonymous principal differs from typed wildcard",
+			a:     `{"Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"*"}]}`,
+			b:     `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"*"},"Action":"s3:GetObject","Resource":"*"}]}`,
+			equal: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := policiesSemanticallyEqual(tt.a, tt.b); got != tt.equal {
+				ca, _ := canonicalizePolicyJSON(tt.a)
+				cb, _ := canonicalizePolicyJSON(tt.b)
+				t.Fatalf("policiesSemanticallyEqual = %v, want %v\n a: %s\n b: %s", got, tt.equal, ca, cb)
+			}
+		})
+	}
+
+	if _, err := canonicalizePolicyJSON(`not json`); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+	if !policiesSemanticallyEqual(" not json ", "not json") {
+		t.Fatal("expected whitespace-only differences in non-JSON input to be equal")
+	}
+}
+
+func TestPolicyStateFromRemote(t *testing.T) {
+	t.Parallel()
+
+	configured := `{
+  "Version": "2012-10-17",
+  "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject", "s3:ListBucket"], "Resource": "*"}]
+}`
+
+	tests := []struct {
+		name   string
+		state  string
+		remote string
+		want   string
+	}{
+		{
+			name:   "formatting only",
+			state:  configured,
+			remote: `{"Statement":{"Resource":"*","Action":["s3:ListBucket","s3:GetObject"],"Effect":"Allow"},"Version":"2012-10-17"}`,
+			want:   configured,
+		},
+		{
+			name:   "content changed",
+			state:  configured,
+			remote: `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:*", "Resource": "*"}]}`,
+			want:   `{"Statement":[{"Action":"s3:*","Effect":"Allow","Resource":"*"}],"Version":"2012-10-17"}`,
+		},
+		{
+			name:   "empty state after import",
+			remote: `{"Version": "2012-10-17", "Statement": []}`,
+			want:   `{"Statement":[],"Version":"2012-10-17"}`,
+		},
+		{
+			name:   "remote not json",
+			state:  configured,
+			remote: "not json",
+			want:   "not json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := policyStateFromRemote(tt.state, tt.remote); got != tt.want {
+				t.Fatalf("unexpected state policy:\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePolicyDocument(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		policy   string
+		errors   []string
+		warnings []string
+		unknown  []string
+	}{
+		{
+			name:   "enforced actions",
+			policy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:Get*","s3:List*","s3:Put*"],"Resource":["arn:aws:s3:::bucket","arn:aws:s3:::bucket/*"]}]}`,
+		},
+		{
+			name:   "admin wildcard",
+			policy: `{"Statement":{"Effect":"Allow","Action":"*","Resource":"*"}}`,
+		},
+		{
+			name:     "ignored actions",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:getobject","iam:ListUsers","s3:Get*"],"Resource":"*"}]}`,
+			warnings: []string{"SeaweedFS ignores s3:GetObject, s3:getobject, iam:ListUsers in identity policies"},
+		},
+		{
+			name:    "typo in action",
+			policy:  `{"Version":"2012-10-17","Statement":[{"Sid":"Read","Effect":"Allow","Action":"s3:GetObjects","Resource":"*"}]}`,
+			unknown: []string{`statement "Read": unknown action "s3:GetObjects", did you mean "s3:GetObject"?`},
+		},
+		{
+			name:    "unknown action without suggestion",
+			policy:  `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:CreateAccessPoint","Resource":"*"}]}`,
+			unknown: []string{`statement 1: unknown action "s3:CreateAccessPoint"`},
+		},
+		{
+			name:     "actions missing from the known list are valid",
+			policy:   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetAccelerateConfiguration","s3:PutInventoryConfiguration","s3:GetIntelligentTieringConfiguration"],"Resource":"*"}]}`,
+			warnings: []string{"SeaweedFS ignores s3:GetAccelerateConfiguration, s3:PutInventoryConfiguration, s3:GetIntelligentTieringConfiguration in identity policies"},
+		},
+		{
+			name:    "wildcard without match",
+			policy:  `{"Statement":[{"Effect":"Allow","Action":"s3:Fetch*","Resource":"*"}]}`,
+			unknown: []string{`statement 1: action "s3:Fetch*" does not match any known S3 action`},
+		},
+		{
+			name:   "malformed action and ARN",
+			policy: `{"Statement":[{"Effect":"Allow","Action":"GetObject","Resource":"bucket/*"}]}`,
+			errors: []string{
+				`statement 1: malformed action "GetObject"`,
+				`statement 1: malformed resource ARN "bucket/*"`,
+			},
+		},
+		{
+			name:   "wrong version and effect",
+			policy: `{"Version":"2012-10-18","Statement":[{"Effect":"allow","Action":"s3:*","Resource":"*"}]}`,
+			errors: []string{
+				`unsupported policy Version "2012-10-18"`,
+				`statement 1: Effect must be "Allow" or "Deny"`,
+			},
+		},
+		{
+			name:   "missing and conflicting elements",
+			policy: `{"Statement":[{"Effect":"Allow","Action":"s3:*","NotAction":"s3:Get*"}]}`,
+			errors: []string{
+				"statement 1: Action and NotAction cannot both be set",
+				"statement 1: one of Resource or NotResource is required",
+			},
+		},
+		{
+			name:   "invalid JSON",
+			policy: `{"Statement":`,
+			errors: []string{"invalid policy JSON"},
+		},
+		{
+			name:   "no statements",
+			policy: `{"Version":"2012-10-17"}`,
+			errors: []string{"policy must contain at least one Statement"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := validatePolicyDocument(tt.policy)
+			assertMessages := func(kind string, got []string, want []string) {
+				t.Helper()
+				if len(got) != len(want) {
+					t.Fatalf("expected %d %s, got %q", len(want), kind, got)
+				}
+				for i := range want {
+					if !strings.Contains(got[i], want[i]) {
+						t.Fatalf("%s[%d] = %q, want it to contain %q", kind, i, got[i], want[i])
+					}
+				}
+			}
+			assertMessages("errors", result.Errors, tt.errors)
+			assertMessages("warnings", result.Warnings, tt.warnings)
+			assertMessages("unknown actions", result.UnknownActions, tt.unknown)
+		})
+	}
+}
+
+func TestRenderPolicyDocument(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		doc       policyDocument
+		sources   []string
+		overrides []string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name: "statements",
+			doc: policyDocument{
+				Statements: []policyStatement{
+					{
+						Sid:       "Read",
+						Actions:   []string{"s3:ListBucket", "s3:GetObject"},
+						Resources: []string{"arn:aws:s3:::data/*"},
+						Conditions: []policyCondition{
+							{Test: "StringLike", Variable: "s3:prefix", Values: []string{"home/"}},
+						},
+					},
+					{
+						Effect:     "Deny",
+						NotActions: []string{"s3:*"},
+						Resources:  []string{"*"},
+						Principals: []policyPrincipal{{Type: "*", Identifiers: []string{"*"}}},
+					},
+				},
+			},
+			want: `{"Statement":[{"Action":["s3:GetObject","s3:ListBucket"],"Condition":{"StringLike":{"s3:prefix":"home/"}},"Effect":"Allow","Resource":"arn:aws:s3:::data/*","Sid":"Read"},{"Effect":"Deny","NotAction":"s3:*","Principal":"*","Resource":"*"}],"Version":"2012-10-17"}`,
+		},
+		{
+			name: "source and override merge by sid",
+			doc: policyDocument{
+				Statements: []policyStatement{
+					{Sid: "B", Actions: []string{"s3:PutObject"}, Resources: []string{"*"}},
+				},
+			},
+			sources: []string{
+				`{"Version":"2012-10-17","Id":"base","Statement":[{"Sid":"A","Effect":"Allow","Action":"s3:GetObject","Resource":"*"},{"Sid":"B","Effect":"Allow","Action":"s3:*","Resource":"*"}]}`,
+			},
+			overrides: []string{
+				`{"Statement":{"Sid":"A","Effect":"Deny","Action":"s3:GetObject","Resource":"*"}}`,
+			},
+			want: `{"Id":"base","Statement":[{"Action":"s3:GetObject","Effect":"Deny","Resource":"*","Sid":"A"},{"Action":"s3:PutObject","Effect":"Allow","Resource":"*","Sid":"B"}],"Version":"2012-10-17"}`,
+		},
+		{
+			name: "principal types",
+			doc: policyDocument{
+				Statements: []policyStatement{
+					{
+						Actions:   []string{"s3:GetObject"},
+						Resources: []string{"*"},
+						Principals: []policyPrincipal{
+							{Type: "AWS", Identifiers: []string{"arn:aws:iam::000000000000:user/b", "arn:aws:iam::000000000000:user/a"}},
+						},
+					},
+				},
+			},
+			want: `{"Statement":[{"Action":"s3:GetObject","Effect":"Allow","Principal":{"AWS":["arn:aws:iam::000000000000:user/a","arn:aws:iam::000000000000:user/b"]},"Resource":"*"}],"Version":"2012-10-17"}`,
+		},
+		{
+			name: "duplicate sid",
+			doc: policyDocument{
+				Statements: []policyStatement{{Sid: "A"}, {Sid: "A"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid source",
+			sources: []string{"{"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := renderPolicyDocument(tt.doc, tt.sources, tt.overrides)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("renderPolicyDocument failed: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("unexpected policy:\n got: %s\nwant: %s", got, tt.want)
+			}
+			normalized, err := normalizeJSONString(got)
+			if err != nil || normalized != got {
+				t.Fatalf("rendered policy is not normalized: %s", got)
+			}
+		})
+	}
+}
+
+func TestResolveProviderSettings(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	credentialsFile := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(credentialsFile, []byte(`# comment
+[default]
+aws_access_key_id = DEFAULT_KEY
+aws_secret_access_key = DEFAULT_SECRET
+
+[profile ci]
+aws_access_key_id=CI_KEY
+aws_secret_access_key=CI_SECRET
+`), 0o600); err != nil {
+		t.Fatalf("write credentials file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		configured providerSettings
+		env        map[string]string
+		want       providerSettings
+		wantErr    bool
+	}{
+		{
+			name: "configuration wins over environment",
+			configured: providerSettings{
+				Endpoint:  "https://hcl",
+				AccessKey: "HCL_KEY",
+				SecretKey: "HCL_SECRET",
+			},
+			env: map[string]string{
+				"SEAWEEDFS_ENDPOINT":   "https://env",
+				"SEAWEEDFS_ACCESS_KEY": "ENV_KEY",
+				"SEAWEEDFS_SECRET_KEY": "ENV_SECRET",
+			},
+			want: providerSettings{Endpoint: "https://hcl", Region: "us-east-1", AccessKey: "HCL_KEY", SecretKey: "HCL_SECRET"},
+		},
+		{
+			name: "seaweedfs environment wins over aws environment",
+			env: map[string]string{
+				"SEAWEEDFS_ENDPOINT":    "https://seaweedfs",
+				"AWS_ENDPOINT_URL":      "https://aws",
+				"SEAWEEDFS_ACCESS_KEY":  "SW_KEY",
+				"AWS_ACCESS_KEY_ID":     "AWS_KEY",
+				"AWS_SECRET_ACCESS_KEY": "AWS_SECRET",
+				"AWS_REGION":            "eu-north-1",
+			},
+			want: providerSettings{Endpoint: "https://seaweedfs", Region: "eu-north-1", AccessKey: "SW_KEY", SecretKey: "AWS_SECRET"},
+		},
+		{
+			name:       "filer endpoint from environment",
+			configured: providerSettings{Endpoint: "https://hcl", AccessKey: "HCL_KEY", SecretKey: "HCL_SECRET"},
+			env:        map[string]string{"SEAWEEDFS_FILER_ENDPOINT": "http://filer:8888"},
+			want:       providerSettings{Endpoint: "https://hcl", Region: "us-east-1", AccessKey: "HCL_KEY", SecretKey: "HCL_SECRET", FilerEndpoint: "http://filer:8888"},
+		},
+		{
+			name:       "filer grpc endpoint from environment",
+			configured: providerSettings{Endpoint: "https://hcl", AccessKey: "HCL_KEY", SecretKey: "HCL_SECRET", FilerEndpoint: "http://filer:8888"},
+			env:        map[string]string{"SEAWEEDFS_FILER_GRPC_ENDPOINT": "filer:28888"},
+			want:       providerSettings{Endpoint: "https://hcl", Region: "us-east-1", AccessKey: "HCL_KEY", SecretKey: "HCL_SECRET", FilerEndpoint: "http://filer:8888", FilerGRPCEndpoint: "filer:28888"},
+		},
+		{
+			name:       "master endpoint from configuration over environment",
+			configured: providerSettings{Endpoint: "https://hcl", AccessKey: "HCL_KEY", SecretKey: "HCL_SECRET", MasterEndpoint: "http://master-hcl:9333"},
+			env:        map[string]string{"SEAWEEDFS_MASTER_ENDPOINT": "http://master:9333"},
+			want:       providerSettings{Endpoint: "https://hcl", Region: "us-east-1", AccessKey: "HCL_KEY", SecretKey: "HCL_SECRET", MasterEndpoint: "http://master-hcl:9333"},
+		},
+		{
+			name:       "shared credentials default profile",
+			configured: providerSettings{Endpoint: "https://hcl", SharedCredentialsFile: credentialsFile},
+			want:       providerSettings{Endpoint: "https://hcl", Region: "us-east-1", AccessKey: "DEFAULT_KEY", SecretKey: "DEFAULT_SECRET", SharedCredentialsFile: credentialsFile},
+		},
+		{
+			name:       "shared credentials profile from environment",
+			configured: providerSettings{Endpoint: "https://hcl"},
+			env: map[string]string{
+				"AWS_SHARED_CREDENTIALS_FILE": credentialsFile,
+				"SEAWEEDFS_PROFILE":           "ci",
+			},
+			want: providerSettings{Endpoint: "https://hcl", Region: "us-east-1", AccessKey: "CI_KEY", SecretKey: "CI_SECRET", SharedCredentialsFile: credentialsFile, Profile: "ci"},
+		},
+		{
+			name:       "unknown profile",
+			configured: providerSettings{Endpoint: "https://hcl", SharedCredentialsFile: credentialsFile, Profile: "missing"},
+			wantErr:    true,
+		},
+		{
+			name:       "missing explicit file",
+			configured: providerSettings{Endpoint: "https://hcl", SharedCredentialsFile: filepath.Join(dir, "missing")},
+			wantErr:    true,
+		},
+		{
+			name:       "missing endpoint",
+			configured: providerSettings{AccessKey: "KEY", SecretKey: "SECRET"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := resolveProviderSettings(tt.configured, func(key string) string {
+				return tt.env[key]
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got settings: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolve settings: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("unexpected settings:\n got: %+v\nwant: %+v", got, tt.want)
+			}
+		})
 	}
 }
 
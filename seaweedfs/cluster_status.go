@@ -0,0 +1,135 @@
+package seaweedfs
+
+import "sort"
+
+// defaultDiskType is how SeaweedFS names the disk type of volumes created
+// without one.
+const defaultDiskType = "hdd"
+
+// clusterCapacity counts volume slots; erasure coded shards are not counted.
+type clusterCapacity struct {
+	Max     int64
+	Volumes int64
+	Free    int64
+}
+
+func (c *clusterCapacity) add(other clusterCapacity) {
+	c.Max += other.Max
+	c.Volumes += other.Volumes
+	c.Free += other.Free
+}
+
+type clusterSummary struct {
+	clusterCapacity
+	Leader      string
+	Peers       []string
+	DataCenters []clusterDataCenterSummary
+	DiskTypes   []clusterDiskTypeSummary
+}
+
+type clusterDataCenterSummary struct {
+	clusterCapacity
+	ID    string
+	Racks []clusterRackSummary
+}
+
+type clusterRackSummary struct {
+	clusterCapacity
+	ID        string
+	DataNodes []clusterDataNodeSummary
+}
+
+type clusterDataNodeSummary struct {
+	clusterCapacity
+	URL       string
+	PublicURL string
+	EcShards  int64
+}
+
+type clusterDiskTypeSummary struct {
+	clusterCapacity
+	DiskType string
+}
+
+// summarizeCluster sums up from the data nodes, as some versions only report
+// capacity on some levels.
+func summarizeCluster(topology masterTopology, status masterClusterStatus) clusterSummary {
+	summary := clusterSummary{Leader: status.Leader, Peers: status.Peers}
+	diskTypes := map[string]*clusterCapacity{}
+
+	for _, dc := range topology.Topology.DataCenters {
+		dcSummary := clusterDataCenterSummary{ID: dc.ID}
+		for _, rack := range dc.Racks {
+			rackSummary := clusterRackSummary{ID: rack.ID}
+			for _, node := range rack.DataNodes {
+				nodeSummary := clusterDataNodeSummary{URL: node.URL, PublicURL: node.PublicURL, EcShards: int64(node.EcShards)}
+				for _, disk := range dataNodeDisks(node) {
+					capacity := clusterCapacity{Max: disk.MaxVolumeCount, Volumes: disk.VolumeCount, Free: disk.FreeVolumeCount}
+					nodeSummary.add(capacity)
+					if diskTypes[disk.Type] == nil {
+						diskTypes[disk.Type] = &clusterCapacity{}
+					}
+					diskTypes[disk.Type].add(capacity)
+				}
+				rackSummary.add(nodeSummary.clusterCapacity)
+				rackSummary.DataNodes = append(rackSummary.DataNodes, nodeSummary)
+			}
+			dcSummary.add(rackSummary.clusterCapacity)
+			dcSummary.Racks = append(dcSummary.Racks, rackSummary)
+		}
+		summary.add(dcSummary.clusterCapacity)
+		summary.DataCenters = append(summary.DataCenters, dcSummary)
+	}
+
+	if len(summary.DataCenters) == 0 {
+		summary.Max = int64(topology.Topology.Max)
+		summary.Free = int64(topology.Topology.Free)
+		summary.Volumes = summary.Max - summary.Free
+	}
+
+	names := make([]string, 0, len(diskTypes))
+	for name := range diskTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		summary.DiskTypes = append(summary.DiskTypes, clusterDiskTypeSummary{DiskType: name, clusterCapacity: *diskTypes[name]})
+	}
+	return summary
+}
+
+// dataNodeDisks attributes node totals to the default disk type on versions
+// without multi-disk support.
+func dataNodeDisks(node masterDataNode) []masterDiskInfo {
+	if len(node.DiskInfos) == 0 {
+		return []masterDiskInfo{normalizeDiskInfo("", masterDiskInfo{
+			MaxVolumeCount: int64(node.Max),
+			VolumeCount:    int64(node.Volumes),
+		})}
+	}
+
+	keys := make([]string, 0, len(node.DiskInfos))
+	for key := range node.DiskInfos {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	disks := make([]masterDiskInfo, 0, len(keys))
+	for _, key := range keys {
+		disks = append(disks, normalizeDiskInfo(key, node.DiskInfos[key]))
+	}
+	return disks
+}
+
+func normalizeDiskInfo(key string, disk masterDiskInfo) masterDiskInfo {
+	if disk.Type == "" {
+		disk.Type = key
+	}
+	if disk.Type == "" {
+		disk.Type = defaultDiskType
+	}
+	if disk.FreeVolumeCount == 0 && disk.MaxVolumeCount > disk.VolumeCount {
+		disk.FreeVolumeCount = disk.MaxVolumeCount - disk.VolumeCount
+	}
+	return disk
+}
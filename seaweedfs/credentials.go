@@ -0,0 +1,143 @@
+package seaweedfs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// providerSettings holds the connection settings after environment and
+// shared credentials file fallbacks have been applied.
+type providerSettings struct {
+	Endpoint              string
+	Region                string
+	AccessKey             string
+	SecretKey             string
+	SharedCredentialsFile string
+	Profile               string
+	FilerEndpoint         string
+	FilerGRPCEndpoint     string
+	MasterEndpoint        string
+}
+
+// resolveProviderSettings fills settings that were not set in the provider
+// block. Values are taken, in order, from the configuration, SEAWEEDFS_*
+// environment variables, their AWS_* equivalents and, for the key pair, the
+// shared credentials file.
+func resolveProviderSettings(configured providerSettings, getenv func(string) string) (providerSettings, error) {
+	settings := providerSettings{
+		Endpoint:              firstNonEmpty(configured.Endpoint, getenv("SEAWEEDFS_ENDPOINT"), getenv("AWS_ENDPOINT_URL_S3"), getenv("AWS_ENDPOINT_URL")),
+		Region:                firstNonEmpty(configured.Region, getenv("SEAWEEDFS_REGION"), getenv("AWS_REGION"), getenv("AWS_DEFAULT_REGION"), "us-east-1"),
+		AccessKey:             firstNonEmpty(configured.AccessKey, getenv("SEAWEEDFS_ACCESS_KEY"), getenv("AWS_ACCESS_KEY_ID")),
+		SecretKey:             firstNonEmpty(configured.SecretKey, getenv("SEAWEEDFS_SECRET_KEY"), getenv("AWS_SECRET_ACCESS_KEY")),
+		SharedCredentialsFile: firstNonEmpty(configured.SharedCredentialsFile, getenv("SEAWEEDFS_SHARED_CREDENTIALS_FILE"), getenv("AWS_SHARED_CREDENTIALS_FILE")),
+		Profile:               firstNonEmpty(configured.Profile, getenv("SEAWEEDFS_PROFILE"), getenv("AWS_PROFILE")),
+		FilerEndpoint:         firstNonEmpty(configured.FilerEndpoint, getenv("SEAWEEDFS_FILER_ENDPOINT")),
+		FilerGRPCEndpoint:     firstNonEmpty(configured.FilerGRPCEndpoint, getenv("SEAWEEDFS_FILER_GRPC_ENDPOINT")),
+		MasterEndpoint:        firstNonEmpty(configured.MasterEndpoint, getenv("SEAWEEDFS_MASTER_ENDPOINT")),
+	}
+
+	if settings.Endpoint == "" {
+		return settings, errors.New("endpoint is required: set it in the provider block or via SEAWEEDFS_ENDPOINT")
+	}
+
+	if settings.AccessKey == "" || settings.SecretKey == "" {
+		if err := settings.loadSharedCredentials(); err != nil {
+			return settings, err
+		}
+	}
+	if settings.AccessKey == "" || settings.SecretKey == "" {
+		return settings, errors.New("access_key and secret_key are required: set them in the provider block, via SEAWEEDFS_ACCESS_KEY/SEAWEEDFS_SECRET_KEY, or in a shared credentials file")
+	}
+
+	return settings, nil
+}
+
+// loadSharedCredentials reads the missing key pair from the shared
+// credentials file. The default ~/.aws/credentials file is optional; an
+// explicitly configured file or profile must exist.
+func (s *providerSettings) loadSharedCredentials() error {
+	path := s.SharedCredentialsFile
+	explicit := path != "" || s.Profile != ""
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+
+	profiles, err := readSharedCredentialsFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !explicit {
+			return nil
+		}
+		return fmt.Errorf("read shared credentials file: %w", err)
+	}
+
+	profileName := firstNonEmpty(s.Profile, "default")
+	profile, ok := profiles[profileName]
+	if !ok {
+		if !explicit {
+			return nil
+		}
+		return fmt.Errorf("profile %q not found in shared credentials file %s", profileName, path)
+	}
+
+	if s.AccessKey == "" && s.SecretKey == "" {
+		s.AccessKey = profile["aws_access_key_id"]
+		s.SecretKey = profile["aws_secret_access_key"]
+	}
+	return nil
+}
+
+// readSharedCredentialsFile parses the INI format used by AWS shared
+// credentials files into profile name -> key -> value.
+func readSharedCredentialsFile(path string) (map[string]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%s:%d: malformed section header", path, lineNumber)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			name = strings.TrimSpace(strings.TrimPrefix(name, "profile "))
+			if profiles[name] == nil {
+				profiles[name] = map[string]string{}
+			}
+			current = profiles[name]
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, lineNumber)
+		}
+		if current == nil {
+			return nil, fmt.Errorf("%s:%d: key outside of a profile section", path, lineNumber)
+		}
+		current[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
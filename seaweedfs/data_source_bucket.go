@@ -0,0 +1,165 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &bucketDataSource{}
+	_ datasource.DataSourceWithConfigure = &bucketDataSource{}
+)
+
+func NewBucketDataSource() datasource.DataSource {
+	return &bucketDataSource{}
+}
+
+type bucketDataSource struct {
+	client *iamClient
+}
+
+type bucketDataSourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	Bucket           types.String `tfsdk:"bucket"`
+	ARN              types.String `tfsdk:"arn"`
+	Tags             types.Map    `tfsdk:"tags"`
+	VersioningStatus types.String `tfsdk:"versioning_status"`
+	Policy           types.String `tfsdk:"policy"`
+	CreationDate     types.String `tfsdk:"creation_date"`
+}
+
+func (d *bucketDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket"
+}
+
+func (d *bucketDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads an existing SeaweedFS S3 bucket that is managed elsewhere.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this data source. Equals bucket.",
+			},
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Bucket name.",
+			},
+			"arn": schema.StringAttribute{
+				Computed:    true,
+				Description: "Bucket ARN.",
+			},
+			"tags": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Bucket tags.",
+			},
+			"versioning_status": schema.StringAttribute{
+				Computed:    true,
+				Description: "Versioning state of the bucket, `Enabled` or `Suspended`. Null if versioning was never enabled.",
+			},
+			"policy": schema.StringAttribute{
+				Computed:    true,
+				Description: "Bucket policy document in normalized JSON form. Null if the bucket has no policy.",
+			},
+			"creation_date": schema.StringAttribute{
+				Computed:    true,
+				Description: "Creation time of the bucket in RFC 3339 format.",
+			},
+		},
+	}
+}
+
+func (d *bucketDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	d.client = data.client
+}
+
+func (d *bucketDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config bucketDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	bucket := config.Bucket.ValueString()
+
+	if err := d.client.HeadBucket(ctx, bucket); err != nil {
+		if isNoSuchBucketError(err) {
+			resp.Diagnostics.AddError("Bucket not found", fmt.Sprintf("Bucket %q does not exist.", bucket))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read bucket", err.Error())
+		return
+	}
+
+	tags, err := d.client.GetBucketTags(ctx, bucket)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read bucket tags", err.Error())
+		return
+	}
+	tagsValue, diags := terraformMapFromStringMap(ctx, tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Gateways without versioning support report NotImplemented; their
+	// buckets have no versioning.
+	versioningStatus, err := d.client.GetBucketVersioning(ctx, bucket)
+	if err != nil && !isNotImplementedError(err) {
+		resp.Diagnostics.AddError("Failed to read bucket versioning", err.Error())
+		return
+	}
+
+	policy, err := d.client.GetBucketPolicy(ctx, bucket)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read bucket policy", err.Error())
+		return
+	}
+	policyValue := types.StringNull()
+	if policy != "" {
+		normalized, err := normalizeJSONString(policy)
+		if err != nil {
+			normalized = policy
+		}
+		policyValue = types.StringValue(normalized)
+	}
+
+	// HeadBucket has no creation date; take it from the bucket listing.
+	creationDate := types.StringNull()
+	buckets, err := d.client.ListBuckets(ctx, bucket)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list buckets", err.Error())
+		return
+	}
+	for _, info := range buckets {
+		if info.Name == bucket && !info.CreationDate.IsZero() {
+			creationDate = types.StringValue(info.CreationDate.UTC().Format(time.RFC3339))
+		}
+	}
+
+	state := bucketDataSourceModel{
+		ID:               types.StringValue(bucket),
+		Bucket:           types.StringValue(bucket),
+		ARN:              types.StringValue("arn:aws:s3:::" + bucket),
+		Tags:             tagsValue,
+		VersioningStatus: types.StringNull(),
+		Policy:           policyValue,
+		CreationDate:     creationDate,
+	}
+	if versioningStatus != "" {
+		state.VersioningStatus = types.StringValue(versioningStatus)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
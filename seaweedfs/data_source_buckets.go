@@ -0,0 +1,202 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &bucketsDataSource{}
+	_ datasource.DataSourceWithConfigure = &bucketsDataSource{}
+)
+
+func NewBucketsDataSource() datasource.DataSource {
+	return &bucketsDataSource{}
+}
+
+type bucketsDataSource struct {
+	client *iamClient
+}
+
+type bucketsDataSourceModel struct {
+	ID        types.String           `tfsdk:"id"`
+	Prefix    types.String           `tfsdk:"prefix"`
+	NameRegex types.String           `tfsdk:"name_regex"`
+	Tags      types.Map              `tfsdk:"tags"`
+	Names     types.List             `tfsdk:"names"`
+	Buckets   []bucketsDataItemModel `tfsdk:"buckets"`
+}
+
+type bucketsDataItemModel struct {
+	Bucket       types.String `tfsdk:"bucket"`
+	ARN          types.String `tfsdk:"arn"`
+	CreationDate types.String `tfsdk:"creation_date"`
+}
+
+func (d *bucketsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_buckets"
+}
+
+func (d *bucketsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists SeaweedFS S3 buckets, optionally filtered by name prefix, name regular expression and tags.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this data source, derived from the filters.",
+			},
+			"prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return buckets whose name starts with this prefix.",
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return buckets whose name matches this RE2 regular expression.",
+				Validators: []validator.String{
+					stringRegex(),
+				},
+			},
+			"tags": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Only return buckets that have all of these tags. Reading tags costs one request per bucket left after the name filters.",
+			},
+			"names": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Names of the matching buckets, sorted.",
+			},
+			"buckets": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Matching buckets, sorted by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"bucket": schema.StringAttribute{
+							Computed:    true,
+							Description: "Bucket name.",
+						},
+						"arn": schema.StringAttribute{
+							Computed:    true,
+							Description: "Bucket ARN.",
+						},
+						"creation_date": schema.StringAttribute{
+							Computed:    true,
+							Description: "Creation time of the bucket in RFC 3339 format.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *bucketsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	d.client = data.client
+}
+
+func (d *bucketsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config bucketsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !config.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(config.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid name_regex", err.Error())
+			return
+		}
+	}
+	wantTags, diags := stringMapFromTerraformMap(ctx, config.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	buckets, err := d.client.ListBuckets(ctx, config.Prefix.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list buckets", err.Error())
+		return
+	}
+
+	names := []string{}
+	config.Buckets = []bucketsDataItemModel{}
+	for _, bucket := range buckets {
+		if nameRegex != nil && !nameRegex.MatchString(bucket.Name) {
+			continue
+		}
+		if len(wantTags) > 0 {
+			tags, err := d.client.GetBucketTags(ctx, bucket.Name)
+			if err != nil {
+				if isNoSuchBucketError(err) {
+					// Deleted since it was listed.
+					continue
+				}
+				resp.Diagnostics.AddError("Failed to read bucket tags", err.Error())
+				return
+			}
+			if !bucketTagsMatch(tags, wantTags) {
+				continue
+			}
+		}
+
+		creationDate := types.StringNull()
+		if !bucket.CreationDate.IsZero() {
+			creationDate = types.StringValue(bucket.CreationDate.UTC().Format(time.RFC3339))
+		}
+		names = append(names, bucket.Name)
+		config.Buckets = append(config.Buckets, bucketsDataItemModel{
+			Bucket:       types.StringValue(bucket.Name),
+			ARN:          types.StringValue("arn:aws:s3:::" + bucket.Name),
+			CreationDate: creationDate,
+		})
+	}
+
+	namesValue, diags := types.ListValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	config.Names = namesValue
+
+	filters := []string{config.Prefix.ValueString(), config.NameRegex.ValueString()}
+	for key, value := range wantTags {
+		filters = append(filters, key+"="+value)
+	}
+	sort.Strings(filters[2:])
+	config.ID = types.StringValue(strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(strings.Join(filters, "\n")))), 10))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// bucketTagsMatch reports whether tags contains every key of want with the
+// same value.
+func bucketTagsMatch(tags map[string]string, want map[string]string) bool {
+	for key, value := range want {
+		if got, ok := tags[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,263 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &clusterStatusDataSource{}
+	_ datasource.DataSourceWithConfigure = &clusterStatusDataSource{}
+)
+
+func NewClusterStatusDataSource() datasource.DataSource {
+	return &clusterStatusDataSource{}
+}
+
+type clusterStatusDataSource struct {
+	data *providerData
+}
+
+type clusterStatusDataSourceModel struct {
+	ID          types.String                 `tfsdk:"id"`
+	Leader      types.String                 `tfsdk:"leader"`
+	Peers       types.List                   `tfsdk:"peers"`
+	MaxVolumes  types.Int64                  `tfsdk:"max_volumes"`
+	VolumeCount types.Int64                  `tfsdk:"volume_count"`
+	FreeVolumes types.Int64                  `tfsdk:"free_volumes"`
+	DataCenters []clusterDataCenterModel     `tfsdk:"data_centers"`
+	DiskTypes   []clusterDiskTypeStatusModel `tfsdk:"disk_types"`
+}
+
+type clusterDataCenterModel struct {
+	ID          types.String       `tfsdk:"id"`
+	MaxVolumes  types.Int64        `tfsdk:"max_volumes"`
+	VolumeCount types.Int64        `tfsdk:"volume_count"`
+	FreeVolumes types.Int64        `tfsdk:"free_volumes"`
+	Racks       []clusterRackModel `tfsdk:"racks"`
+}
+
+type clusterRackModel struct {
+	ID          types.String           `tfsdk:"id"`
+	MaxVolumes  types.Int64            `tfsdk:"max_volumes"`
+	VolumeCount types.Int64            `tfsdk:"volume_count"`
+	FreeVolumes types.Int64            `tfsdk:"free_volumes"`
+	DataNodes   []clusterDataNodeModel `tfsdk:"data_nodes"`
+}
+
+type clusterDataNodeModel struct {
+	URL          types.String `tfsdk:"url"`
+	PublicURL    types.String `tfsdk:"public_url"`
+	MaxVolumes   types.Int64  `tfsdk:"max_volumes"`
+	VolumeCount  types.Int64  `tfsdk:"volume_count"`
+	FreeVolumes  types.Int64  `tfsdk:"free_volumes"`
+	EcShardCount types.Int64  `tfsdk:"ec_shard_count"`
+}
+
+type clusterDiskTypeStatusModel struct {
+	DiskType    types.String `tfsdk:"disk_type"`
+	MaxVolumes  types.Int64  `tfsdk:"max_volumes"`
+	VolumeCount types.Int64  `tfsdk:"volume_count"`
+	FreeVolumes types.Int64  `tfsdk:"free_volumes"`
+}
+
+func (d *clusterStatusDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster_status"
+}
+
+func (d *clusterStatusDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	capacity := func(level string) map[string]schema.Attribute {
+		return map[string]schema.Attribute{
+			"max_volumes": schema.Int64Attribute{
+				Computed:    true,
+				Description: fmt.Sprintf("Volume slots of the %s.", level),
+			},
+			"volume_count": schema.Int64Attribute{
+				Computed:    true,
+				Description: fmt.Sprintf("Volumes stored on the %s.", level),
+			},
+			"free_volumes": schema.Int64Attribute{
+				Computed:    true,
+				Description: fmt.Sprintf("Free volume slots of the %s.", level),
+			},
+		}
+	}
+	withCapacity := func(level string, attributes map[string]schema.Attribute) map[string]schema.Attribute {
+		for name, attribute := range capacity(level) {
+			attributes[name] = attribute
+		}
+		return attributes
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Reads the topology and capacity of the SeaweedFS cluster from the master, for example to check for free volume slots in a `precondition`. Free slots do not account for erasure coded shards. Requires `master_endpoint`.",
+		Attributes: withCapacity("cluster", map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this data source. Equals leader.",
+			},
+			"leader": schema.StringAttribute{
+				Computed:    true,
+				Description: "Address of the master leader.",
+			},
+			"peers": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Addresses of the master raft peers.",
+			},
+			"data_centers": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Data centers of the cluster.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: withCapacity("data center", map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:    true,
+							Description: "Data center name.",
+						},
+						"racks": schema.ListNestedAttribute{
+							Computed:    true,
+							Description: "Racks of the data center.",
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: withCapacity("rack", map[string]schema.Attribute{
+									"id": schema.StringAttribute{
+										Computed:    true,
+										Description: "Rack name.",
+									},
+									"data_nodes": schema.ListNestedAttribute{
+										Computed:    true,
+										Description: "Volume servers of the rack.",
+										NestedObject: schema.NestedAttributeObject{
+											Attributes: withCapacity("volume server", map[string]schema.Attribute{
+												"url": schema.StringAttribute{
+													Computed:    true,
+													Description: "Address of the volume server.",
+												},
+												"public_url": schema.StringAttribute{
+													Computed:    true,
+													Description: "Public address of the volume server.",
+												},
+												"ec_shard_count": schema.Int64Attribute{
+													Computed:    true,
+													Description: "Erasure coded shards stored on the volume server.",
+												},
+											}),
+										},
+									},
+								}),
+							},
+						},
+					}),
+				},
+			},
+			"disk_types": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Capacity per disk type, sorted by disk type. Volume servers without multi-disk support are counted as `hdd`.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: withCapacity("disk type", map[string]schema.Attribute{
+						"disk_type": schema.StringAttribute{
+							Computed:    true,
+							Description: "Disk type, for example `hdd` or `ssd`.",
+						},
+					}),
+				},
+			},
+		}),
+	}
+}
+
+func (d *clusterStatusDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	d.data = data
+}
+
+func (d *clusterStatusDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	master, err := d.data.requireMaster("seaweedfs_cluster_status")
+	if err != nil {
+		resp.Diagnostics.AddError("Master not configured", err.Error())
+		return
+	}
+
+	topology, err := master.DirStatus(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read cluster topology", err.Error())
+		return
+	}
+	status, err := master.ClusterStatus(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read cluster status", err.Error())
+		return
+	}
+
+	summary := summarizeCluster(topology, status)
+	peers := make([]attr.Value, 0, len(summary.Peers))
+	for _, peer := range summary.Peers {
+		peers = append(peers, types.StringValue(peer))
+	}
+	peersValue, diags := types.ListValue(types.StringType, peers)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := clusterStatusDataSourceModel{
+		ID:          types.StringValue(summary.Leader),
+		Leader:      types.StringValue(summary.Leader),
+		Peers:       peersValue,
+		MaxVolumes:  types.Int64Value(summary.Max),
+		VolumeCount: types.Int64Value(summary.Volumes),
+		FreeVolumes: types.Int64Value(summary.Free),
+		DataCenters: []clusterDataCenterModel{},
+		DiskTypes:   []clusterDiskTypeStatusModel{},
+	}
+	for _, dc := range summary.DataCenters {
+		dcModel := clusterDataCenterModel{
+			ID:          types.StringValue(dc.ID),
+			MaxVolumes:  types.Int64Value(dc.Max),
+			VolumeCount: types.Int64Value(dc.Volumes),
+			FreeVolumes: types.Int64Value(dc.Free),
+			Racks:       []clusterRackModel{},
+		}
+		for _, rack := range dc.Racks {
+			rackModel := clusterRackModel{
+				ID:          types.StringValue(rack.ID),
+				MaxVolumes:  types.Int64Value(rack.Max),
+				VolumeCount: types.Int64Value(rack.Volumes),
+				FreeVolumes: types.Int64Value(rack.Free),
+				DataNodes:   []clusterDataNodeModel{},
+			}
+			for _, node := range rack.DataNodes {
+				rackModel.DataNodes = append(rackModel.DataNodes, clusterDataNodeModel{
+					URL:          types.StringValue(node.URL),
+					PublicURL:    types.StringValue(node.PublicURL),
+					MaxVolumes:   types.Int64Value(node.Max),
+					VolumeCount:  types.Int64Value(node.Volumes),
+					FreeVolumes:  types.Int64Value(node.Free),
+					EcShardCount: types.Int64Value(node.EcShards),
+				})
+			}
+			dcModel.Racks = append(dcModel.Racks, rackModel)
+		}
+		state.DataCenters = append(state.DataCenters, dcModel)
+	}
+	for _, disk := range summary.DiskTypes {
+		state.DiskTypes = append(state.DiskTypes, clusterDiskTypeStatusModel{
+			DiskType:    types.StringValue(disk.DiskType),
+			MaxVolumes:  types.Int64Value(disk.Max),
+			VolumeCount: types.Int64Value(disk.Volumes),
+			FreeVolumes: types.Int64Value(disk.Free),
+		})
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
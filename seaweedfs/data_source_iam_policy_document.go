@@ -0,0 +1,259 @@
+package seaweedfs
+
+import (
+	"context"
+	"hash/crc32"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &iamPolicyDocumentDataSource{}
+
+func NewIAMPolicyDocumentDataSource() datasource.DataSource {
+	return &iamPolicyDocumentDataSource{}
+}
+
+type iamPolicyDocumentDataSource struct{}
+
+type iamPolicyDocumentDataSourceModel struct {
+	ID                      types.String                      `tfsdk:"id"`
+	PolicyID                types.String                      `tfsdk:"policy_id"`
+	Version                 types.String                      `tfsdk:"version"`
+	SourcePolicyDocuments   types.List                        `tfsdk:"source_policy_documents"`
+	OverridePolicyDocuments types.List                        `tfsdk:"override_policy_documents"`
+	Statement               []iamPolicyDocumentStatementModel `tfsdk:"statement"`
+	JSON                    types.String                      `tfsdk:"json"`
+}
+
+type iamPolicyDocumentStatementModel struct {
+	Sid           types.String                      `tfsdk:"sid"`
+	Effect        types.String                      `tfsdk:"effect"`
+	Actions       types.Set                         `tfsdk:"actions"`
+	NotActions    types.Set                         `tfsdk:"not_actions"`
+	Resources     types.Set                         `tfsdk:"resources"`
+	NotResources  types.Set                         `tfsdk:"not_resources"`
+	Principals    []iamPolicyDocumentPrincipalModel `tfsdk:"principals"`
+	NotPrincipals []iamPolicyDocumentPrincipalModel `tfsdk:"not_principals"`
+	Condition     []iamPolicyDocumentConditionModel `tfsdk:"condition"`
+}
+
+type iamPolicyDocumentPrincipalModel struct {
+	Type        types.String `tfsdk:"type"`
+	Identifiers types.Set    `tfsdk:"identifiers"`
+}
+
+type iamPolicyDocumentConditionModel struct {
+	Test     types.String `tfsdk:"test"`
+	Variable types.String `tfsdk:"variable"`
+	Values   types.Set    `tfsdk:"values"`
+}
+
+func (d *iamPolicyDocumentDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iam_policy_document"
+}
+
+func (d *iamPolicyDocumentDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	principalBlock := schema.SetNestedBlock{
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"type": schema.StringAttribute{
+					Required:    true,
+					Description: "Principal type, for example `AWS` or `*`.",
+				},
+				"identifiers": schema.SetAttribute{
+					Required:    true,
+					ElementType: types.StringType,
+					Description: "Principal identifiers.",
+				},
+			},
+		},
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Generates an IAM policy document in JSON format from HCL, for use with `seaweedfs_iam_user_policy` and `seaweedfs_bucket_policy`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"policy_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Id of the policy document.",
+			},
+			"version": schema.StringAttribute{
+				Optional:    true,
+				Description: "Policy language version. Defaults to `2012-10-17`.",
+			},
+			"source_policy_documents": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "JSON policy documents merged into this document. Statements in `statement` blocks replace source statements with the same `sid`.",
+			},
+			"override_policy_documents": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "JSON policy documents merged last. Their statements replace any statement with the same `sid`.",
+			},
+			"json": schema.StringAttribute{
+				Computed:    true,
+				Description: "Rendered policy document, in the normalized JSON form stored by the policy resources.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"statement": schema.ListNestedBlock{
+				Description: "Policy statement.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"sid": schema.StringAttribute{
+							Optional:    true,
+							Description: "Statement identifier, used for merging with source and override documents.",
+						},
+						"effect": schema.StringAttribute{
+							Optional:    true,
+							Description: "`Allow` or `Deny`. Defaults to `Allow`.",
+							Validators: []validator.String{
+								stringOneOf("Allow", "Deny"),
+							},
+						},
+						"actions": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Actions the statement applies to.",
+						},
+						"not_actions": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Actions the statement does not apply to.",
+						},
+						"resources": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Resource ARNs the statement applies to.",
+						},
+						"not_resources": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Resource ARNs the statement does not apply to.",
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"principals":     principalBlock,
+						"not_principals": principalBlock,
+						"condition": schema.SetNestedBlock{
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"test": schema.StringAttribute{
+										Required:    true,
+										Description: "Condition operator, for example `StringLike`.",
+									},
+									"variable": schema.StringAttribute{
+										Required:    true,
+										Description: "Condition key, for example `s3:prefix`.",
+									},
+									"values": schema.SetAttribute{
+										Required:    true,
+										ElementType: types.StringType,
+										Description: "Values to compare the key with.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *iamPolicyDocumentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config iamPolicyDocumentDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	doc := policyDocument{
+		Version: config.Version.ValueString(),
+		ID:      config.PolicyID.ValueString(),
+	}
+	for _, statement := range config.Statement {
+		rendered, diags := policyStatementFromModel(ctx, statement)
+		resp.Diagnostics.Append(diags...)
+		doc.Statements = append(doc.Statements, rendered)
+	}
+
+	var sources []string
+	if !config.SourcePolicyDocuments.IsNull() {
+		resp.Diagnostics.Append(config.SourcePolicyDocuments.ElementsAs(ctx, &sources, false)...)
+	}
+	var overrides []string
+	if !config.OverridePolicyDocuments.IsNull() {
+		resp.Diagnostics.Append(config.OverridePolicyDocuments.ElementsAs(ctx, &overrides, false)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rendered, err := renderPolicyDocument(doc, sources, overrides)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to render policy document", err.Error())
+		return
+	}
+
+	config.JSON = types.StringValue(rendered)
+	config.ID = types.StringValue(strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(rendered))), 10))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+func policyStatementFromModel(ctx context.Context, model iamPolicyDocumentStatementModel) (policyStatement, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var d diag.Diagnostics
+
+	statement := policyStatement{
+		Sid:    model.Sid.ValueString(),
+		Effect: model.Effect.ValueString(),
+	}
+	statement.Actions, d = stringSliceFromTerraformSet(ctx, model.Actions)
+	diags.Append(d...)
+	statement.NotActions, d = stringSliceFromTerraformSet(ctx, model.NotActions)
+	diags.Append(d...)
+	statement.Resources, d = stringSliceFromTerraformSet(ctx, model.Resources)
+	diags.Append(d...)
+	statement.NotResources, d = stringSliceFromTerraformSet(ctx, model.NotResources)
+	diags.Append(d...)
+
+	statement.Principals, d = policyPrincipalsFromModel(ctx, model.Principals)
+	diags.Append(d...)
+	statement.NotPrincipals, d = policyPrincipalsFromModel(ctx, model.NotPrincipals)
+	diags.Append(d...)
+
+	for _, condition := range model.Condition {
+		values, d := stringSliceFromTerraformSet(ctx, condition.Values)
+		diags.Append(d...)
+		statement.Conditions = append(statement.Conditions, policyCondition{
+			Test:     condition.Test.ValueString(),
+			Variable: condition.Variable.ValueString(),
+			Values:   values,
+		})
+	}
+	return statement, diags
+}
+
+func policyPrincipalsFromModel(ctx context.Context, models []iamPolicyDocumentPrincipalModel) ([]policyPrincipal, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	principals := make([]policyPrincipal, 0, len(models))
+	for _, model := range models {
+		identifiers, d := stringSliceFromTerraformSet(ctx, model.Identifiers)
+		diags.Append(d...)
+		principals = append(principals, policyPrincipal{
+			Type:        model.Type.ValueString(),
+			Identifiers: identifiers,
+		})
+	}
+	return principals, diags
+}
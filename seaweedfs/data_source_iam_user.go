@@ -0,0 +1,189 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &iamUserDataSource{}
+	_ datasource.DataSourceWithConfigure = &iamUserDataSource{}
+)
+
+func NewIAMUserDataSource() datasource.DataSource {
+	return &iamUserDataSource{}
+}
+
+type iamUserDataSource struct {
+	client *iamClient
+}
+
+type iamUserDataSourceModel struct {
+	ID                 types.String `tfsdk:"id"`
+	Name               types.String `tfsdk:"name"`
+	ARN                types.String `tfsdk:"arn"`
+	UserID             types.String `tfsdk:"user_id"`
+	Path               types.String `tfsdk:"path"`
+	Tags               types.Map    `tfsdk:"tags"`
+	InlinePolicyNames  types.List   `tfsdk:"inline_policy_names"`
+	AttachedPolicyARNs types.List   `tfsdk:"attached_policy_arns"`
+	AccessKeyIDs       types.List   `tfsdk:"access_key_ids"`
+}
+
+func (d *iamUserDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iam_user"
+}
+
+func (d *iamUserDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads an existing SeaweedFS IAM user together with its policies and access key ids.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this data source. Equals name.",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "IAM user name.",
+			},
+			"arn": schema.StringAttribute{
+				Computed:    true,
+				Description: "IAM user ARN.",
+			},
+			"user_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "IAM user ID.",
+			},
+			"path": schema.StringAttribute{
+				Computed:    true,
+				Description: "IAM user path.",
+			},
+			"tags": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "IAM user tags. Empty if the server does not support user tags.",
+			},
+			"inline_policy_names": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Names of the inline policies of the user, sorted.",
+			},
+			"attached_policy_arns": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "ARNs of the managed policies attached to the user, sorted.",
+			},
+			"access_key_ids": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Access key ids of the user, sorted. Secret keys are never returned.",
+			},
+		},
+	}
+}
+
+func (d *iamUserDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	d.client = data.client
+}
+
+func (d *iamUserDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config iamUserDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	name := config.Name.ValueString()
+
+	user, err := d.client.GetUser(ctx, name)
+	if err != nil {
+		if isNoSuchEntityError(err) {
+			resp.Diagnostics.AddError("IAM user not found", fmt.Sprintf("IAM user %q does not exist.", name))
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read IAM user", iamErrorDetail(err))
+		return
+	}
+
+	tags, err := d.client.ListUserTags(ctx, name)
+	if err != nil {
+		if !isNotImplementedError(err) {
+			resp.Diagnostics.AddError("Failed to read IAM user tags", iamErrorDetail(err))
+			return
+		}
+		tags = map[string]string{}
+	}
+
+	inlinePolicies, err := d.client.ListUserPolicies(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list IAM user policies", iamErrorDetail(err))
+		return
+	}
+
+	attached, err := d.client.ListAttachedUserPolicies(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list attached IAM user policies", iamErrorDetail(err))
+		return
+	}
+	attachedARNs := make([]string, 0, len(attached))
+	for _, policy := range attached {
+		attachedARNs = append(attachedARNs, policy.PolicyArn)
+	}
+
+	accessKeys, err := d.client.ListAccessKeys(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list IAM access keys", iamErrorDetail(err))
+		return
+	}
+	accessKeyIDs := make([]string, 0, len(accessKeys))
+	for _, key := range accessKeys {
+		accessKeyIDs = append(accessKeyIDs, key.AccessKeyID)
+	}
+
+	userPath := user.User.Path
+	if userPath == "" {
+		userPath = "/"
+	}
+	state := iamUserDataSourceModel{
+		ID:     types.StringValue(user.User.UserName),
+		Name:   types.StringValue(user.User.UserName),
+		ARN:    types.StringValue(user.User.Arn),
+		UserID: types.StringValue(user.User.UserID),
+		Path:   types.StringValue(userPath),
+	}
+
+	var diags diag.Diagnostics
+	state.Tags, diags = terraformMapFromStringMap(ctx, tags)
+	resp.Diagnostics.Append(diags...)
+	state.InlinePolicyNames, diags = sortedTerraformList(ctx, inlinePolicies)
+	resp.Diagnostics.Append(diags...)
+	state.AttachedPolicyARNs, diags = sortedTerraformList(ctx, attachedARNs)
+	resp.Diagnostics.Append(diags...)
+	state.AccessKeyIDs, diags = sortedTerraformList(ctx, accessKeyIDs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// sortedTerraformList returns values as a sorted list, empty rather than null
+// when there are none.
+func sortedTerraformList(ctx context.Context, values []string) (types.List, diag.Diagnostics) {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return types.ListValueFrom(ctx, types.StringType, sorted)
+}
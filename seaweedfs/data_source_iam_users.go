@@ -0,0 +1,173 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &iamUsersDataSource{}
+	_ datasource.DataSourceWithConfigure = &iamUsersDataSource{}
+)
+
+func NewIAMUsersDataSource() datasource.DataSource {
+	return &iamUsersDataSource{}
+}
+
+type iamUsersDataSource struct {
+	client *iamClient
+}
+
+type iamUsersDataSourceModel struct {
+	ID         types.String            `tfsdk:"id"`
+	PathPrefix types.String            `tfsdk:"path_prefix"`
+	NameRegex  types.String            `tfsdk:"name_regex"`
+	Names      types.List              `tfsdk:"names"`
+	ARNs       types.List              `tfsdk:"arns"`
+	Users      []iamUsersDataItemModel `tfsdk:"users"`
+}
+
+type iamUsersDataItemModel struct {
+	Name   types.String `tfsdk:"name"`
+	ARN    types.String `tfsdk:"arn"`
+	UserID types.String `tfsdk:"user_id"`
+	Path   types.String `tfsdk:"path"`
+}
+
+func (d *iamUsersDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iam_users"
+}
+
+func (d *iamUsersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists SeaweedFS IAM users, optionally filtered by path prefix and name regular expression.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this data source, derived from the filters.",
+			},
+			"path_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return users whose path starts with this prefix, for example `/team/`.",
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return users whose name matches this RE2 regular expression.",
+				Validators: []validator.String{
+					stringRegex(),
+				},
+			},
+			"names": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Names of the matching users, sorted.",
+			},
+			"arns": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "ARNs of the matching users, in the order of names.",
+			},
+			"users": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Matching users, sorted by name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "IAM user name.",
+						},
+						"arn": schema.StringAttribute{
+							Computed:    true,
+							Description: "IAM user ARN.",
+						},
+						"user_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "IAM user ID.",
+						},
+						"path": schema.StringAttribute{
+							Computed:    true,
+							Description: "IAM user path.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *iamUsersDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	d.client = data.client
+}
+
+func (d *iamUsersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config iamUsersDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !config.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(config.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid name_regex", err.Error())
+			return
+		}
+	}
+
+	users, err := d.client.ListUsers(ctx, config.PathPrefix.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list IAM users", iamErrorDetail(err))
+		return
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].UserName < users[j].UserName })
+
+	names := []string{}
+	arns := []string{}
+	config.Users = []iamUsersDataItemModel{}
+	for _, user := range users {
+		if nameRegex != nil && !nameRegex.MatchString(user.UserName) {
+			continue
+		}
+		names = append(names, user.UserName)
+		arns = append(arns, user.Arn)
+		config.Users = append(config.Users, iamUsersDataItemModel{
+			Name:   types.StringValue(user.UserName),
+			ARN:    types.StringValue(user.Arn),
+			UserID: types.StringValue(user.UserID),
+			Path:   types.StringValue(user.Path),
+		})
+	}
+
+	namesValue, diags := types.ListValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	arnsValue, diags := types.ListValueFrom(ctx, types.StringType, arns)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	config.Names = namesValue
+	config.ARNs = arnsValue
+
+	filters := config.PathPrefix.ValueString() + "\n" + config.NameRegex.ValueString()
+	config.ID = types.StringValue(strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(filters))), 10))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
@@ -0,0 +1,211 @@
+package seaweedfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// filerClient talks to the SeaweedFS filer HTTP API, which stores cluster
+// configuration such as the S3 identities file next to regular files.
+type filerClient struct {
+	endpoint string
+	http     *http.Client
+}
+
+// filerEntry is the subset of the filer entry metadata returned by
+// GET <path>?metadata=true that the provider uses.
+type filerEntry struct {
+	FullPath string            `json:"FullPath"`
+	Mtime    string            `json:"Mtime"`
+	Crtime   string            `json:"Crtime"`
+	Mode     uint32            `json:"Mode"`
+	Mime     string            `json:"Mime"`
+	FileSize uint64            `json:"FileSize"`
+	Extended map[string][]byte `json:"Extended"`
+}
+
+// filerModeDir is os.ModeDir, the bit the filer sets for directories.
+const filerModeDir = 1 << 31
+
+func (e filerEntry) IsDirectory() bool {
+	return e.Mode&filerModeDir != 0
+}
+
+// filerUpdateAttempts bounds the read-modify-write retries of UpdateFile.
+const filerUpdateAttempts = 5
+
+var errFilerConflict = errors.New("file was modified concurrently")
+
+type filerError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e filerError) Error() string {
+	return fmt.Sprintf("filer HTTP%d: %s", e.StatusCode, e.Message)
+}
+
+func newFilerClient(endpoint string, tlsConfig *tls.Config) (*filerClient, error) {
+	if endpoint == "" {
+		return nil, errors.New("filer endpoint is required")
+	}
+
+	return &filerClient{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		http: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			Timeout:   30 * time.Second,
+		},
+	}, nil
+}
+
+// ReadFile returns the content of the file at filePath.
+func (c *filerClient) ReadFile(ctx context.Context, filePath string) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, filePath, nil, "", nil)
+}
+
+// WriteFile creates or replaces the file at filePath. Missing parent
+// directories are created by the filer.
+func (c *filerClient) WriteFile(ctx context.Context, filePath string, data []byte) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", path.Base(filePath))
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	_, err = c.do(ctx, http.MethodPost, filePath, nil, writer.FormDataContentType(), &body)
+	return err
+}
+
+// UpdateFile applies update to the content of the file at filePath. update
+// receives nil for a missing file; returning the content unchanged skips the
+// write. The filer has no conditional writes, so the file is read again right
+// before writing and after it, and the cycle restarts on fresh content when
+// another writer changed it in between.
+func (c *filerClient) UpdateFile(ctx context.Context, filePath string, update func(current []byte) ([]byte, error)) error {
+	for attempt := 0; attempt < filerUpdateAttempts; attempt++ {
+		current, err := c.readFileIfExists(ctx, filePath)
+		if err != nil {
+			return err
+		}
+		updated, err := update(current)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(updated, current) {
+			return nil
+		}
+
+		latest, err := c.readFileIfExists(ctx, filePath)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(latest, current) {
+			continue
+		}
+		if err := c.WriteFile(ctx, filePath, updated); err != nil {
+			return err
+		}
+
+		written, err := c.readFileIfExists(ctx, filePath)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(written, updated) {
+			return nil
+		}
+	}
+	return fmt.Errorf("update %s: %w after %d attempts", filePath, errFilerConflict, filerUpdateAttempts)
+}
+
+func (c *filerClient) readFileIfExists(ctx context.Context, filePath string) ([]byte, error) {
+	content, err := c.ReadFile(ctx, filePath)
+	if isFilerNotFoundError(err) {
+		return nil, nil
+	}
+	return content, err
+}
+
+// Mkdir creates the directory at dirPath, including missing parents.
+func (c *filerClient) Mkdir(ctx context.Context, dirPath string) error {
+	_, err := c.do(ctx, http.MethodPost, strings.TrimRight(dirPath, "/")+"/", url.Values{"op": {"mkdir"}}, "", nil)
+	return err
+}
+
+// Stat returns the metadata of the file or directory at entryPath.
+func (c *filerClient) Stat(ctx context.Context, entryPath string) (filerEntry, error) {
+	data, err := c.do(ctx, http.MethodGet, entryPath, url.Values{"metadata": {"true"}}, "", nil)
+	if err != nil {
+		return filerEntry{}, err
+	}
+
+	var entry filerEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return filerEntry{}, fmt.Errorf("decode filer entry %s: %w", entryPath, err)
+	}
+	return entry, nil
+}
+
+// Delete removes the file or directory at entryPath. Non-empty directories
+// are only removed when recursive is set.
+func (c *filerClient) Delete(ctx context.Context, entryPath string, recursive bool) error {
+	query := url.Values{}
+	if recursive {
+		query.Set("recursive", "true")
+	}
+	_, err := c.do(ctx, http.MethodDelete, entryPath, query, "", nil)
+	return err
+}
+
+func (c *filerClient) do(ctx context.Context, method string, filePath string, query url.Values, contentType string, body io.Reader) ([]byte, error) {
+	requestURL := c.endpoint + (&url.URL{Path: "/" + strings.TrimLeft(filePath, "/")}).EscapedPath()
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, filerError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	return data, nil
+}
+
+func isFilerNotFoundError(err error) bool {
+	var apiErr filerError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
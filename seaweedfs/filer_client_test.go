@@ -0,0 +1,775 @@
+package seaweedfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// newFakeFiler serves files from an in-memory map the way the filer HTTP API
+// does: GET returns content or, with metadata=true, the entry; multipart POST
+// stores the "file" field; POST ?op=mkdir creates directories and DELETE
+// removes entries, refusing non-empty directories unless recursive=true.
+func newFakeFiler(t *testing.T) (*httptest.Server, map[string][]byte) {
+	t.Helper()
+
+	var mu sync.Mutex
+	files := map[string][]byte{}
+	dirs := map[string]bool{}
+	hasChildren := func(dir string) bool {
+		for name := range files {
+			if strings.HasPrefix(name, dir+"/") {
+				return true
+			}
+		}
+		for name := range dirs {
+			if strings.HasPrefix(name, dir+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		name := strings.TrimRight(r.URL.Path, "/")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Get("metadata") == "true":
+			mode := uint32(0o644)
+			if dirs[name] {
+				mode = filerModeDir | 0o755
+			} else if _, ok := files[name]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"FullPath": name, "Mode": mode, "FileSize": len(files[name])})
+		case r.Method == http.MethodGet:
+			content, ok := files[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(content)
+		case r.Method == http.MethodPost && r.URL.Query().Get("op") == "mkdir":
+			if _, ok := files[name]; ok {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			for dir := name; dir != ""; dir = dir[:strings.LastIndex(dir, "/")] {
+				dirs[dir] = true
+			}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPost:
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(err.Error()))
+				return
+			}
+			content, err := io.ReadAll(file)
+			if err != nil {
+				t.Errorf("read uploaded file: %v", err)
+			}
+			files[r.URL.Path] = content
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodDelete:
+			_, isFile := files[name]
+			if !isFile && !dirs[name] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if hasChildren(name) && r.URL.Query().Get("recursive") != "true" {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte(`{"error":"fail to delete non-empty folder"}`))
+				return
+			}
+			for entry := range files {
+				if entry == name || strings.HasPrefix(entry, name+"/") {
+					delete(files, entry)
+				}
+			}
+			for entry := range dirs {
+				if entry == name || strings.HasPrefix(entry, name+"/") {
+					delete(dirs, entry)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, files
+}
+
+func TestFilerClientReadWrite(t *testing.T) {
+	t.Parallel()
+
+	srv, files := newFakeFiler(t)
+	client, err := newFilerClient(srv.URL+"/", nil)
+	if err != nil {
+		t.Fatalf("new filer client: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.ReadFile(ctx, "/etc/iam/identity.json"); !isFilerNotFoundError(err) {
+		t.Fatalf("expected not found error, got: %v", err)
+	}
+
+	if err := client.WriteFile(ctx, "/etc/iam/identity.json", []byte(`{"identities":[]}`)); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if got := string(files["/etc/iam/identity.json"]); got != `{"identities":[]}` {
+		t.Fatalf("unexpected stored content: %s", got)
+	}
+
+	content, err := client.ReadFile(ctx, "etc/iam/identity.json")
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if string(content) != `{"identities":[]}` {
+		t.Fatalf("unexpected content: %s", content)
+	}
+
+	if _, err := newFilerClient("", nil); err == nil {
+		t.Fatal("expected an error for an empty endpoint")
+	}
+}
+
+func TestFilerClientDirectories(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newFakeFiler(t)
+	client, err := newFilerClient(srv.URL, nil)
+	if err != nil {
+		t.Fatalf("new filer client: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := client.Stat(ctx, "/data/team-a"); !isFilerNotFoundError(err) {
+		t.Fatalf("expected not found before mkdir, got: %v", err)
+	}
+
+	if err := client.Mkdir(ctx, "/data/team-a/"); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	entry, err := client.Stat(ctx, "/data/team-a")
+	if err != nil {
+		t.Fatalf("stat directory: %v", err)
+	}
+	if !entry.IsDirectory() || entry.FullPath != "/data/team-a" {
+		t.Fatalf("unexpected directory entry: %+v", entry)
+	}
+	parent, err := client.Stat(ctx, "/data")
+	if err != nil || !parent.IsDirectory() {
+		t.Fatalf("expected parent directory to be created, got %+v, %v", parent, err)
+	}
+
+	if err := client.WriteFile(ctx, "/data/team-a/readme.txt", []byte("hello")); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	file, err := client.Stat(ctx, "/data/team-a/readme.txt")
+	if err != nil {
+		t.Fatalf("stat file: %v", err)
+	}
+	if file.IsDirectory() || file.FileSize != 5 {
+		t.Fatalf("unexpected file entry: %+v", file)
+	}
+
+	if err := client.Delete(ctx, "/data/team-a", false); err == nil || isFilerNotFoundError(err) {
+		t.Fatalf("expected non-recursive delete of a non-empty directory to fail, got: %v", err)
+	}
+	if err := client.Delete(ctx, "/data/team-a", true); err != nil {
+		t.Fatalf("recursive delete: %v", err)
+	}
+	if _, err := client.Stat(ctx, "/data/team-a/readme.txt"); !isFilerNotFoundError(err) {
+		t.Fatalf("expected content to be deleted, got: %v", err)
+	}
+	if err := client.Delete(ctx, "/data", false); err != nil {
+		t.Fatalf("delete empty directory: %v", err)
+	}
+}
+
+func TestS3IdentitiesRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	existing := []byte(`{
+  "identities": [
+    {
+      "name": "admin",
+      "credentials": [{"accessKey": "AK1", "secretKey": "SK1"}],
+      "actions": ["Admin"],
+      "account": {"id": "acc-1"}
+    },
+    {
+      "name": "legacy",
+      "credentials": [{"access_key": "AK2", "secret_key": "SK2"}],
+      "actions": ["Read:logs"]
+    }
+  ],
+  "accounts": [{"id": "acc-1", "displayName": "Platform"}]
+}`)
+
+	parsed, err := parseS3Identities(existing)
+	if err != nil {
+		t.Fatalf("parse identities: %v", err)
+	}
+	want := []s3Identity{
+		{Name: "admin", Actions: []string{"Admin"}, Credentials: []s3IdentityCredential{{AccessKey: "AK1", SecretKey: "SK1"}}},
+		{Name: "legacy", Actions: []string{"Read:logs"}, Credentials: []s3IdentityCredential{{AccessKey: "AK2", SecretKey: "SK2"}}},
+	}
+	if !reflect.DeepEqual(parsed, want) {
+		t.Fatalf("unexpected identities:\n got: %+v\nwant: %+v", parsed, want)
+	}
+
+	updated := []s3Identity{
+		{Name: "admin", Actions: []string{"Admin", "Read"}, Credentials: []s3IdentityCredential{{AccessKey: "AK1", SecretKey: "SK1-rotated"}}},
+		{Name: "anonymous", Actions: []string{"Read:public"}},
+	}
+	rendered, err := renderS3Identities(existing, updated)
+	if err != nil {
+		t.Fatalf("render identities: %v", err)
+	}
+
+	reparsed, err := parseS3Identities(rendered)
+	if err != nil {
+		t.Fatalf("parse rendered identities: %v", err)
+	}
+	if !reflect.DeepEqual(reparsed, updated) {
+		t.Fatalf("unexpected rendered identities:\n got: %+v\nwant: %+v", reparsed, updated)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(rendered, &document); err != nil {
+		t.Fatalf("decode rendered document: %v", err)
+	}
+	if _, ok := document["accounts"]; !ok {
+		t.Fatal("expected the accounts section to be kept")
+	}
+	admin := document["identities"].([]any)[0].(map[string]any)
+	if !reflect.DeepEqual(admin["account"], map[string]any{"id": "acc-1"}) {
+		t.Fatalf("expected unmanaged identity fields to be kept, got: %v", admin)
+	}
+	if strings.Contains(string(rendered), "legacy") {
+		t.Fatal("expected identities missing from the configuration to be removed")
+	}
+
+	empty, err := renderS3Identities(nil, nil)
+	if err != nil {
+		t.Fatalf("render into empty document: %v", err)
+	}
+	if strings.TrimSpace(string(empty)) != "{\n  \"identities\": []\n}" {
+		t.Fatalf("unexpected empty document: %s", empty)
+	}
+
+	if _, err := parseS3Identities([]byte(`{"identities":`)); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
+
+func TestS3IdentityConflicts(t *testing.T) {
+	t.Parallel()
+
+	unique := []s3Identity{
+		{Name: "admin", Credentials: []s3IdentityCredential{{AccessKey: "AK1"}}},
+		{Name: "reader", Credentials: []s3IdentityCredential{{AccessKey: "AK2"}, {AccessKey: ""}}},
+		{Name: "", Credentials: []s3IdentityCredential{{AccessKey: ""}}},
+		{Name: "anonymous"},
+	}
+	if problems := s3IdentityConflicts(unique); len(problems) != 0 {
+		t.Fatalf("expected no conflicts, got %q", problems)
+	}
+
+	duplicated := []s3Identity{
+		{Name: "admin", Credentials: []s3IdentityCredential{{AccessKey: "AK1"}}},
+		{Name: "admin", Credentials: []s3IdentityCredential{{AccessKey: "AK2"}}},
+		{Name: "reader", Credentials: []s3IdentityCredential{{AccessKey: "AK1"}}},
+	}
+	want := []string{
+		`identity name "admin" is used more than once`,
+		`access key "AK1" of identity "reader" is already used by identity "admin"`,
+	}
+	if problems := s3IdentityConflicts(duplicated); !reflect.DeepEqual(problems, want) {
+		t.Fatalf("unexpected conflicts:\n got: %q\nwant: %q", problems, want)
+	}
+}
+
+func TestFilerClientUpdateFile(t *testing.T) {
+	t.Parallel()
+
+	srv, files := newFakeFiler(t)
+	client, err := newFilerClient(srv.URL, nil)
+	if err != nil {
+		t.Fatalf("new filer client: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := client.UpdateFile(ctx, "/etc/app.conf", func(current []byte) ([]byte, error) {
+		if current != nil {
+			t.Fatalf("expected nil content for a missing file, got: %s", current)
+		}
+		return []byte("a"), nil
+	}); err != nil {
+		t.Fatalf("create through update: %v", err)
+	}
+
+	// A concurrent writer changes the file between the first read and the
+	// write; the update must be applied on top of the new content.
+	calls := 0
+	if err := client.UpdateFile(ctx, "/etc/app.conf", func(current []byte) ([]byte, error) {
+		calls++
+		if calls == 1 {
+			if err := client.WriteFile(ctx, "/etc/app.conf", []byte("ab")); err != nil {
+				t.Fatalf("concurrent write: %v", err)
+			}
+		}
+		return append(append([]byte{}, current...), 'c'), nil
+	}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected the update to be retried once, got %d calls", calls)
+	}
+	if got := string(files["/etc/app.conf"]); got != "abc" {
+		t.Fatalf("unexpected content after conflicting update: %s", got)
+	}
+
+	err = client.UpdateFile(ctx, "/etc/app.conf", func(current []byte) ([]byte, error) {
+		if err := client.WriteFile(ctx, "/etc/app.conf", append(current, 'x')); err != nil {
+			t.Fatalf("concurrent write: %v", err)
+		}
+		return []byte("lost"), nil
+	})
+	if !errors.Is(err, errFilerConflict) {
+		t.Fatalf("expected a conflict error, got: %v", err)
+	}
+}
+
+func TestFilerPathConfig(t *testing.T) {
+	t.Parallel()
+
+	existing := []byte(`{
+  "version": 0,
+  "locations": [
+    {"locationPrefix": "/buckets/logs/", "collection": "logs", "ttl": "7d", "dataCenter": "dc1"},
+    {"location_prefix": "/buckets/media/", "replication": "001", "volume_growth_count": "4"}
+  ]
+}`)
+
+	media, found, err := findFilerPathConfig(existing, "/buckets/media/")
+	if err != nil || !found {
+		t.Fatalf("find snake_case rule: found=%v err=%v", found, err)
+	}
+	if want := (filerPathConfig{LocationPrefix: "/buckets/media/", Replication: "001", VolumeGrowthCount: 4}); media != want {
+		t.Fatalf("unexpected rule:\n got: %+v\nwant: %+v", media, want)
+	}
+
+	updated, err := editFilerPathConfig(existing, "/buckets/logs/", func(cfg *filerPathConfig) {
+		*cfg = filerPathConfig{LocationPrefix: "/buckets/logs/", Replication: "010", Fsync: true}
+	})
+	if err != nil {
+		t.Fatalf("edit existing rule: %v", err)
+	}
+	logs, found, err := findFilerPathConfig(updated, "/buckets/logs/")
+	if err != nil || !found {
+		t.Fatalf("find updated rule: found=%v err=%v", found, err)
+	}
+	if want := (filerPathConfig{LocationPrefix: "/buckets/logs/", Replication: "010", Fsync: true}); logs != want {
+		t.Fatalf("unexpected updated rule:\n got: %+v\nwant: %+v", logs, want)
+	}
+	if !strings.Contains(string(updated), `"dataCenter": "dc1"`) || !strings.Contains(string(updated), `"version": 0`) {
+		t.Fatalf("expected unmanaged settings to be kept, got: %s", updated)
+	}
+
+	updated, err = editFilerPathConfig(updated, "/buckets/media/", func(cfg *filerPathConfig) {
+		cfg.Replication = "002"
+		cfg.VolumeGrowthCount = 0
+	})
+	if err != nil {
+		t.Fatalf("edit snake_case rule: %v", err)
+	}
+	if strings.Contains(string(updated), "volume_growth_count") || strings.Contains(string(updated), `"replication": "001"`) {
+		t.Fatalf("expected snake_case settings to be replaced, got: %s", updated)
+	}
+
+	updated, found, err = removeFilerPathConfig(updated, "/buckets/logs/")
+	if err != nil || !found {
+		t.Fatalf("remove rule: found=%v err=%v", found, err)
+	}
+	if _, found, _ := findFilerPathConfig(updated, "/buckets/logs/"); found {
+		t.Fatal("expected the rule to be removed")
+	}
+	if _, found, _ := findFilerPathConfig(updated, "/buckets/media/"); !found {
+		t.Fatal("expected other rules to be kept")
+	}
+	if _, found, _ := removeFilerPathConfig(updated, "/buckets/logs/"); found {
+		t.Fatal("expected removing a missing rule to report not found")
+	}
+
+	created, err := editFilerPathConfig(nil, "/tmp/", func(cfg *filerPathConfig) {
+		cfg.TTL = "1h"
+	})
+	if err != nil {
+		t.Fatalf("edit empty configuration: %v", err)
+	}
+	if got, _, _ := findFilerPathConfig(created, "/tmp/"); got.TTL != "1h" {
+		t.Fatalf("unexpected rule in new configuration: %+v", got)
+	}
+
+	if _, _, err := findFilerPathConfig([]byte(`{"locations": [`), "/"); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
+
+func TestValidateFilerPathSettings(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"000", "001", "210"} {
+		if err := validateReplication(value); err != nil {
+			t.Errorf("replication %q: unexpected error: %v", value, err)
+		}
+	}
+	for _, value := range []string{"", "01", "0001", "abc"} {
+		if err := validateReplication(value); err == nil {
+			t.Errorf("replication %q: expected an error", value)
+		}
+	}
+	for _, value := range []string{"30", "30m", "12h", "7d", "4w", "3M", "1y"} {
+		if err := validateTTL(value); err != nil {
+			t.Errorf("ttl %q: unexpected error: %v", value, err)
+		}
+	}
+	for _, value := range []string{"", "d", "7s", "1.5h"} {
+		if err := validateTTL(value); err == nil {
+			t.Errorf("ttl %q: expected an error", value)
+		}
+	}
+}
+
+func TestEditFilerPathConfig(t *testing.T) {
+	t.Parallel()
+
+	existing := []byte(`{"locations": [{"locationPrefix": "/buckets/logs/", "fsync": true, "replication": "001"}]}`)
+
+	updated, err := editFilerPathConfig(existing, bucketLocationPrefix("logs"), func(cfg *filerPathConfig) {
+		cfg.Replication = "010"
+		cfg.DiskType = "ssd"
+	})
+	if err != nil {
+		t.Fatalf("edit existing rule: %v", err)
+	}
+	got, _, _ := findFilerPathConfig(updated, "/buckets/logs/")
+	if want := (filerPathConfig{LocationPrefix: "/buckets/logs/", Replication: "010", DiskType: "ssd", Fsync: true}); got != want {
+		t.Fatalf("unexpected edited rule:\n got: %+v\nwant: %+v", got, want)
+	}
+
+	updated, err = editFilerPathConfig(updated, "/buckets/logs/", func(cfg *filerPathConfig) {
+		cfg.Replication = ""
+		cfg.DiskType = ""
+		cfg.Fsync = false
+	})
+	if err != nil {
+		t.Fatalf("clear rule: %v", err)
+	}
+	if _, found, _ := findFilerPathConfig(updated, "/buckets/logs/"); found {
+		t.Fatalf("expected a rule without settings to be removed, got: %s", updated)
+	}
+
+	// Settings not managed here, such as dataCenter and rack, keep the rule
+	// alive when all managed settings are cleared.
+	pinned := []byte(`{"locations": [{"locationPrefix": "/buckets/pinned/", "replication": "001", "dataCenter": "dc1", "rack": "rack1"}]}`)
+	updated, err = editFilerPathConfig(pinned, bucketLocationPrefix("pinned"), func(cfg *filerPathConfig) {
+		cfg.Replication = ""
+	})
+	if err != nil {
+		t.Fatalf("clear managed settings: %v", err)
+	}
+	_, locations, err := parseFilerConf(updated)
+	if err != nil {
+		t.Fatalf("parse edited configuration: %v", err)
+	}
+	if want := []map[string]any{{"locationPrefix": "/buckets/pinned/", "dataCenter": "dc1", "rack": "rack1"}}; !reflect.DeepEqual(locations, want) {
+		t.Fatalf("unexpected rules after clearing managed settings:\n got: %v\nwant: %v", locations, want)
+	}
+
+	unchanged, err := editFilerPathConfig(nil, "/buckets/new/", func(cfg *filerPathConfig) {})
+	if err != nil || unchanged != nil {
+		t.Fatalf("expected no content for an empty edit of a missing rule, got %q, %v", unchanged, err)
+	}
+}
+
+func TestSharedBucketPathRule(t *testing.T) {
+	t.Parallel()
+
+	str := func(value string) *string { return &value }
+	enabled := true
+	prefix := bucketLocationPrefix("media")
+
+	// A seaweedfs_bucket and a seaweedfs_filer_path_config manage different
+	// settings of the same rule.
+	bucket := filerPathConfigSettings{Replication: str("001"), Collection: str("media")}
+	pathConfig := filerPathConfigSettings{TTL: str("7d"), Fsync: &enabled}
+
+	data, err := editFilerPathConfigSettings(nil, prefix, bucket, filerPathConfigSettings{})
+	if err != nil {
+		t.Fatalf("write bucket placement: %v", err)
+	}
+	data, err = editFilerPathConfigSettings(data, prefix, pathConfig, filerPathConfigSettings{})
+	if err != nil {
+		t.Fatalf("write path config: %v", err)
+	}
+	cfg, _, _ := findFilerPathConfig(data, prefix)
+	if want := (filerPathConfig{LocationPrefix: prefix, Replication: "001", Collection: "media", TTL: "7d", Fsync: true}); cfg != want {
+		t.Fatalf("unexpected shared rule:\n got: %+v\nwant: %+v", cfg, want)
+	}
+
+	// The bucket Read sees only its own settings, so the path config's
+	// settings are no drift.
+	placement, err := readBucketPlacement(data, "media", bucket)
+	if err != nil {
+		t.Fatalf("read bucket placement: %v", err)
+	}
+	if !reflect.DeepEqual(placement, bucket) {
+		t.Fatalf("unexpected bucket placement:\n got: %+v\nwant: %+v", placement, bucket)
+	}
+
+	// An imported bucket takes over the placement settings of the rule but
+	// not the other settings.
+	placement, err = readBucketPlacement(data, "media", filerPathConfigSettings{})
+	if err != nil {
+		t.Fatalf("read imported bucket placement: %v", err)
+	}
+	if want := (filerPathConfigSettings{Replication: str("001"), TTL: str("7d"), Collection: str("media")}); !reflect.DeepEqual(placement, want) {
+		t.Fatalf("unexpected imported placement:\n got: %+v\nwant: %+v", placement, want)
+	}
+
+	// A managed setting changed out of band is drift.
+	changed, err := editFilerPathConfig(data, prefix, func(cfg *filerPathConfig) {
+		cfg.Replication = "010"
+		cfg.Collection = ""
+	})
+	if err != nil {
+		t.Fatalf("change rule: %v", err)
+	}
+	placement, err = readBucketPlacement(changed, "media", bucket)
+	if err != nil {
+		t.Fatalf("read changed bucket placement: %v", err)
+	}
+	if want := (filerPathConfigSettings{Replication: str("010"), Collection: str("")}); !reflect.DeepEqual(placement, want) {
+		t.Fatalf("unexpected drifted placement:\n got: %+v\nwant: %+v", placement, want)
+	}
+
+	// Dropping collection from the bucket clears only collection.
+	reduced := filerPathConfigSettings{Replication: str("001")}
+	data, err = editFilerPathConfigSettings(data, prefix, reduced, bucket)
+	if err != nil {
+		t.Fatalf("update bucket placement: %v", err)
+	}
+	cfg, _, _ = findFilerPathConfig(data, prefix)
+	if want := (filerPathConfig{LocationPrefix: prefix, Replication: "001", TTL: "7d", Fsync: true}); cfg != want {
+		t.Fatalf("unexpected rule after dropping collection:\n got: %+v\nwant: %+v", cfg, want)
+	}
+
+	// Deleting the path config keeps the bucket's settings; deleting the
+	// bucket as well removes the rule.
+	data, err = editFilerPathConfigSettings(data, prefix, filerPathConfigSettings{}, pathConfig)
+	if err != nil {
+		t.Fatalf("delete path config: %v", err)
+	}
+	cfg, _, _ = findFilerPathConfig(data, prefix)
+	if want := (filerPathConfig{LocationPrefix: prefix, Replication: "001"}); cfg != want {
+		t.Fatalf("unexpected rule after deleting path config:\n got: %+v\nwant: %+v", cfg, want)
+	}
+	data, err = editFilerPathConfigSettings(data, prefix, filerPathConfigSettings{}, reduced)
+	if err != nil {
+		t.Fatalf("delete bucket placement: %v", err)
+	}
+	if _, found, _ := findFilerPathConfig(data, prefix); found {
+		t.Fatalf("expected the rule to be removed, got: %s", data)
+	}
+
+	imported := filerPathConfigSettingsOf(filerPathConfig{LocationPrefix: prefix, Replication: "001", Fsync: true})
+	if want := (filerPathConfigSettings{Replication: str("001"), Fsync: &enabled}); !reflect.DeepEqual(imported, want) {
+		t.Fatalf("unexpected imported settings:\n got: %+v\nwant: %+v", imported, want)
+	}
+}
+
+// newFakeFilerGRPC serves LookupDirectoryEntry and UpdateEntry from an
+// in-memory map of encoded entries keyed by directory and name.
+func newFakeFilerGRPC(t *testing.T) (string, map[string][]byte) {
+	t.Helper()
+
+	var mu sync.Mutex
+	entries := map[string][]byte{}
+	field := func(msg []byte, want protowire.Number) []byte {
+		var value []byte
+		_ = walkProtoFields(msg, func(num protowire.Number, typ protowire.Type, raw []byte) error {
+			if num == want && typ == protowire.BytesType {
+				value, _ = protowire.ConsumeBytes(raw)
+			}
+			return nil
+		})
+		return value
+	}
+
+	srv := grpc.NewServer(
+		grpc.ForceServerCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+			var req []byte
+			if err := stream.RecvMsg(&req); err != nil {
+				return err
+			}
+			method, _ := grpc.MethodFromServerStream(stream)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			directory := string(field(req, filerRequestDirectoryField))
+			var resp []byte
+			switch method {
+			case filerLookupDirectoryEntryMethod:
+				name := string(field(req, filerRequestNameField))
+				entry, ok := entries[directory+"/"+name]
+				if !ok {
+					return status.Errorf(codes.Unknown, "%s/%s: no entry is found in filer store", directory, name)
+				}
+				resp = protowire.AppendTag(resp, filerResponseEntryField, protowire.BytesType)
+				resp = protowire.AppendBytes(resp, entry)
+			case filerUpdateEntryMethod:
+				entry := field(req, filerRequestEntryField)
+				name := string(field(entry, 1))
+				if _, ok := entries[directory+"/"+name]; !ok {
+					return status.Errorf(codes.Unknown, "%s/%s: no entry is found in filer store", directory, name)
+				}
+				entries[directory+"/"+name] = entry
+			default:
+				return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+			}
+			return stream.SendMsg(&resp)
+		}),
+	)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(listener) }()
+	t.Cleanup(srv.Stop)
+	return listener.Addr().String(), entries
+}
+
+func TestFilerGRPCClientBucketQuota(t *testing.T) {
+	t.Parallel()
+
+	addr, entries := newFakeFilerGRPC(t)
+	client, err := newFilerGRPCClient(addr, nil)
+	if err != nil {
+		t.Fatalf("new filer gRPC client: %v", err)
+	}
+
+	// A bucket entry with a name, is_directory and an extended attribute
+	// the quota updates must keep.
+	var entry []byte
+	entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+	entry = protowire.AppendString(entry, "logs")
+	entry = protowire.AppendTag(entry, 2, protowire.VarintType)
+	entry = protowire.AppendVarint(entry, 1)
+	entry = protowire.AppendTag(entry, 5, protowire.BytesType)
+	entry = protowire.AppendBytes(entry, []byte("\n\x03key\x12\x05value"))
+	entries["/buckets/logs"] = entry
+
+	ctx := context.Background()
+	quota, err := client.BucketQuota(ctx, "logs")
+	if err != nil {
+		t.Fatalf("read quota: %v", err)
+	}
+	if quota != 0 {
+		t.Fatalf("expected no quota, got %d", quota)
+	}
+
+	for _, want := range []int64{1 << 30, -(1 << 30), 0} {
+		if err := client.SetBucketQuota(ctx, "logs", want); err != nil {
+			t.Fatalf("set quota %d: %v", want, err)
+		}
+		quota, err := client.BucketQuota(ctx, "logs")
+		if err != nil {
+			t.Fatalf("read quota: %v", err)
+		}
+		if quota != want {
+			t.Fatalf("expected quota %d, got %d", want, quota)
+		}
+	}
+	if got := entries["/buckets/logs"]; string(got) != string(entry) {
+		t.Fatalf("expected the entry to be restored after removing the quota:\n got: %x\nwant: %x", got, entry)
+	}
+
+	if _, err := client.BucketQuota(ctx, "missing"); !isFilerEntryNotFoundError(err) {
+		t.Fatalf("expected not found error, got: %v", err)
+	}
+	if err := client.SetBucketQuota(ctx, "missing", 1); !isFilerEntryNotFoundError(err) {
+		t.Fatalf("expected not found error, got: %v", err)
+	}
+}
+
+func TestFilerGRPCAddress(t *testing.T) {
+	t.Parallel()
+
+	for endpoint, want := range map[string]string{
+		"http://filer:8888":      "filer:18888",
+		"https://10.0.0.1:8443/": "10.0.0.1:18443",
+		"http://[fd00::1]:8888":  "[fd00::1]:18888",
+	} {
+		got, err := filerGRPCAddress(endpoint)
+		if err != nil {
+			t.Fatalf("%s: %v", endpoint, err)
+		}
+		if got != want {
+			t.Fatalf("%s: expected %s, got %s", endpoint, want, got)
+		}
+	}
+	if _, err := filerGRPCAddress("http://filer"); err == nil {
+		t.Fatal("expected an error for an endpoint without port")
+	}
+}
+
+func TestBucketQuotaAttributes(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		bytes    types.Int64
+		enforced types.Bool
+		quota    int64
+	}{
+		{bytes: types.Int64Null(), enforced: types.BoolValue(true), quota: 0},
+		{bytes: types.Int64Value(1024), enforced: types.BoolValue(true), quota: 1024},
+		{bytes: types.Int64Value(1024), enforced: types.BoolValue(false), quota: -1024},
+	} {
+		model := bucketResourceModel{QuotaBytes: tc.bytes, QuotaEnforced: tc.enforced}
+		if got := model.quota(); got != tc.quota {
+			t.Fatalf("quota of %v/%v: expected %d, got %d", tc.bytes, tc.enforced, tc.quota, got)
+		}
+
+		// Read keeps an unset quota unset and reports a quota set outside
+		// Terraform.
+		read := bucketResourceModel{QuotaBytes: types.Int64Value(1), QuotaEnforced: types.BoolValue(true)}
+		read.setQuota(tc.quota)
+		if !read.QuotaBytes.Equal(tc.bytes) || !read.QuotaEnforced.Equal(tc.enforced) {
+			t.Fatalf("read quota %d: got %v/%v", tc.quota, read.QuotaBytes, read.QuotaEnforced)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package seaweedfs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// filerConfFilePath holds the per-path storage rules edited by
+// `weed shell fs.configure`.
+const filerConfFilePath = "/etc/seaweedfs/filer.conf"
+
+// filerPathConfig is one location rule of filer.conf. Zero values mean the
+// setting is not part of the rule, matching how the filer serializes it.
+type filerPathConfig struct {
+	LocationPrefix    string
+	Collection        string
+	Replication       string
+	TTL               string
+	DiskType          string
+	Fsync             bool
+	VolumeGrowthCount int64
+	ReadOnly          bool
+	MaxFileNameLength int64
+}
+
+// filerPathConfigKeys maps the managed rule fields to their protobuf JSON
+// names and the snake_case aliases the filer also accepts.
+var filerPathConfigKeys = map[string]string{
+	"locationPrefix":    "location_prefix",
+	"collection":        "collection",
+	"replication":       "replication",
+	"ttl":               "ttl",
+	"diskType":          "disk_type",
+	"fsync":             "fsync",
+	"volumeGrowthCount": "volume_growth_count",
+	"readOnly":          "read_only",
+	"maxFileNameLength": "max_file_name_length",
+}
+
+// bucketLocationPrefix is the filer path rule prefix of a bucket. The
+// trailing slash keeps the rule from matching buckets sharing a name prefix.
+func bucketLocationPrefix(bucket string) string {
+	return "/buckets/" + bucket + "/"
+}
+
+var (
+	replicationPattern = regexp.MustCompile(`^[0-9]{3}$`)
+	ttlPattern         = regexp.MustCompile(`^[0-9]+[mhdwMy]?$`)
+)
+
+// validateReplication checks a SeaweedFS replication string such as "001":
+// copies in other data centers, other racks and other servers.
+func validateReplication(value string) error {
+	if !replicationPattern.MatchString(value) {
+		return fmt.Errorf("replication %q must be three digits, for example \"001\"", value)
+	}
+	return nil
+}
+
+// validateTTL checks a SeaweedFS TTL such as "30m", "12h", "7d", "4w", "3M" or
+// "1y". A number without unit is in minutes.
+func validateTTL(value string) error {
+	if !ttlPattern.MatchString(value) {
+		return fmt.Errorf("ttl %q must be a number followed by m, h, d, w, M or y, for example \"7d\"", value)
+	}
+	return nil
+}
+
+// findFilerPathConfig returns the rule for prefix from filer.conf content.
+func findFilerPathConfig(data []byte, prefix string) (filerPathConfig, bool, error) {
+	_, locations, err := parseFilerConf(data)
+	if err != nil {
+		return filerPathConfig{}, false, err
+	}
+
+	location := findFilerConfLocation(locations, prefix)
+	if location == nil {
+		return filerPathConfig{}, false, nil
+	}
+	return filerPathConfigFromLocation(location, prefix), true, nil
+}
+
+func findFilerConfLocation(locations []map[string]any, prefix string) map[string]any {
+	for _, location := range locations {
+		if filerConfString(location, "locationPrefix") == prefix {
+			return location
+		}
+	}
+	return nil
+}
+
+func filerPathConfigFromLocation(location map[string]any, prefix string) filerPathConfig {
+	return filerPathConfig{
+		LocationPrefix:    prefix,
+		Collection:        filerConfString(location, "collection"),
+		Replication:       filerConfString(location, "replication"),
+		TTL:               filerConfString(location, "ttl"),
+		DiskType:          filerConfString(location, "diskType"),
+		Fsync:             filerConfBool(location, "fsync"),
+		VolumeGrowthCount: filerConfInt(location, "volumeGrowthCount"),
+		ReadOnly:          filerConfBool(location, "readOnly"),
+		MaxFileNameLength: filerConfInt(location, "maxFileNameLength"),
+	}
+}
+
+// setFilerPathConfig writes the managed settings of cfg into rule, dropping
+// the ones that are unset and leaving all other keys alone.
+func setFilerPathConfig(rule map[string]any, cfg filerPathConfig) {
+	values := map[string]any{
+		"locationPrefix":    cfg.LocationPrefix,
+		"collection":        cfg.Collection,
+		"replication":       cfg.Replication,
+		"ttl":               cfg.TTL,
+		"diskType":          cfg.DiskType,
+		"fsync":             cfg.Fsync,
+		"volumeGrowthCount": cfg.VolumeGrowthCount,
+		"readOnly":          cfg.ReadOnly,
+		"maxFileNameLength": cfg.MaxFileNameLength,
+	}
+	for key, value := range values {
+		delete(rule, filerPathConfigKeys[key])
+		switch typed := value.(type) {
+		case string:
+			if typed == "" {
+				delete(rule, key)
+				continue
+			}
+		case bool:
+			if !typed {
+				delete(rule, key)
+				continue
+			}
+		case int64:
+			if typed == 0 {
+				delete(rule, key)
+				continue
+			}
+		}
+		rule[key] = value
+	}
+}
+
+// editFilerPathConfig changes some settings of the rule for prefix and keeps
+// the others, so several resources can share one rule. A rule left without
+// any setting, including ones not managed here, is removed.
+func editFilerPathConfig(data []byte, prefix string, edit func(cfg *filerPathConfig)) ([]byte, error) {
+	document, locations, err := parseFilerConf(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := filerPathConfig{LocationPrefix: prefix}
+	rule := findFilerConfLocation(locations, prefix)
+	if rule != nil {
+		cfg = filerPathConfigFromLocation(rule, prefix)
+	}
+	edit(&cfg)
+	cfg.LocationPrefix = prefix
+
+	if rule == nil {
+		rule = map[string]any{}
+		locations = append(locations, rule)
+	}
+	setFilerPathConfig(rule, cfg)
+
+	// Keep the rule while it holds anything besides its prefix.
+	if len(rule) > 1 {
+		return renderFilerConf(document, locations)
+	}
+	updated, _, err := removeFilerPathConfig(data, prefix)
+	return updated, err
+}
+
+// filerPathConfigSettings holds the settings of a filer path rule that one
+// resource manages. Nil fields are left to whoever else shares the rule, for
+// example a seaweedfs_bucket and a seaweedfs_filer_path_config on the same
+// bucket prefix.
+type filerPathConfigSettings struct {
+	Collection        *string
+	Replication       *string
+	TTL               *string
+	DiskType          *string
+	Fsync             *bool
+	VolumeGrowthCount *int64
+	ReadOnly          *bool
+	MaxFileNameLength *int64
+}
+
+// filerPathConfigSettingsOf returns every setting of cfg that is set, for
+// taking over an existing rule on import.
+func filerPathConfigSettingsOf(cfg filerPathConfig) filerPathConfigSettings {
+	var s filerPathConfigSettings
+	if cfg.Collection != "" {
+		s.Collection = &cfg.Collection
+	}
+	if cfg.Replication != "" {
+		s.Replication = &cfg.Replication
+	}
+	if cfg.TTL != "" {
+		s.TTL = &cfg.TTL
+	}
+	if cfg.DiskType != "" {
+		s.DiskType = &cfg.DiskType
+	}
+	if cfg.Fsync {
+		s.Fsync = &cfg.Fsync
+	}
+	if cfg.VolumeGrowthCount != 0 {
+		s.VolumeGrowthCount = &cfg.VolumeGrowthCount
+	}
+	if cfg.ReadOnly {
+		s.ReadOnly = &cfg.ReadOnly
+	}
+	if cfg.MaxFileNameLength != 0 {
+		s.MaxFileNameLength = &cfg.MaxFileNameLength
+	}
+	return s
+}
+
+// empty reports whether no setting is managed.
+func (s filerPathConfigSettings) empty() bool {
+	return s == filerPathConfigSettings{}
+}
+
+// apply writes the managed settings into cfg and clears the ones in prior
+// that are no longer managed. Other settings of cfg are left alone.
+func (s filerPathConfigSettings) apply(cfg *filerPathConfig, prior filerPathConfigSettings) {
+	applyFilerSetting(&cfg.Collection, s.Collection, prior.Collection)
+	applyFilerSetting(&cfg.Replication, s.Replication, prior.Replication)
+	applyFilerSetting(&cfg.TTL, s.TTL, prior.TTL)
+	applyFilerSetting(&cfg.DiskType, s.DiskType, prior.DiskType)
+	applyFilerSetting(&cfg.Fsync, s.Fsync, prior.Fsync)
+	applyFilerSetting(&cfg.VolumeGrowthCount, s.VolumeGrowthCount, prior.VolumeGrowthCount)
+	applyFilerSetting(&cfg.ReadOnly, s.ReadOnly, prior.ReadOnly)
+	applyFilerSetting(&cfg.MaxFileNameLength, s.MaxFileNameLength, prior.MaxFileNameLength)
+}
+
+// refresh returns the remote values of the managed settings. Settings that
+// are not managed stay nil, so values another resource put into a shared
+// rule never show up as drift.
+func (s filerPathConfigSettings) refresh(cfg filerPathConfig) filerPathConfigSettings {
+	return filerPathConfigSettings{
+		Collection:        refreshFilerSetting(s.Collection, cfg.Collection),
+		Replication:       refreshFilerSetting(s.Replication, cfg.Replication),
+		TTL:               refreshFilerSetting(s.TTL, cfg.TTL),
+		DiskType:          refreshFilerSetting(s.DiskType, cfg.DiskType),
+		Fsync:             refreshFilerSetting(s.Fsync, cfg.Fsync),
+		VolumeGrowthCount: refreshFilerSetting(s.VolumeGrowthCount, cfg.VolumeGrowthCount),
+		ReadOnly:          refreshFilerSetting(s.ReadOnly, cfg.ReadOnly),
+		MaxFileNameLength: refreshFilerSetting(s.MaxFileNameLength, cfg.MaxFileNameLength),
+	}
+}
+
+func applyFilerSetting[T comparable](field *T, value *T, prior *T) {
+	switch {
+	case value != nil:
+		*field = *value
+	case prior != nil:
+		var zero T
+		*field = zero
+	}
+}
+
+func refreshFilerSetting[T comparable](managed *T, remote T) *T {
+	if managed == nil {
+		return nil
+	}
+	return &remote
+}
+
+// editFilerPathConfigSettings applies settings to the rule for prefix with
+// editFilerPathConfig, clearing those of prior that are no longer managed.
+func editFilerPathConfigSettings(data []byte, prefix string, settings filerPathConfigSettings, prior filerPathConfigSettings) ([]byte, error) {
+	return editFilerPathConfig(data, prefix, func(cfg *filerPathConfig) {
+		settings.apply(cfg, prior)
+	})
+}
+
+// readBucketPlacement returns the remote values of the placement settings
+// a seaweedfs_bucket manages in its rule, for Read to report drift. An
+// imported bucket manages none yet and takes over the placement settings the
+// rule has.
+func readBucketPlacement(data []byte, bucket string, managed filerPathConfigSettings) (filerPathConfigSettings, error) {
+	cfg, _, err := findFilerPathConfig(data, bucketLocationPrefix(bucket))
+	if err != nil {
+		return filerPathConfigSettings{}, err
+	}
+	if managed.empty() {
+		all := filerPathConfigSettingsOf(cfg)
+		return filerPathConfigSettings{Collection: all.Collection, Replication: all.Replication, TTL: all.TTL, DiskType: all.DiskType}, nil
+	}
+	return managed.refresh(cfg), nil
+}
+
+// removeFilerPathConfig deletes the rule for prefix. found reports whether a
+// rule was present.
+func removeFilerPathConfig(data []byte, prefix string) ([]byte, bool, error) {
+	document, locations, err := parseFilerConf(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	kept := make([]map[string]any, 0, len(locations))
+	for _, location := range locations {
+		if filerConfString(location, "locationPrefix") != prefix {
+			kept = append(kept, location)
+		}
+	}
+	if len(kept) == len(locations) {
+		return data, false, nil
+	}
+
+	out, err := renderFilerConf(document, kept)
+	return out, true, err
+}
+
+func parseFilerConf(data []byte) (map[string]any, []map[string]any, error) {
+	document := map[string]any{}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, nil, fmt.Errorf("invalid filer configuration: %w", err)
+		}
+	}
+
+	entries, _ := document["locations"].([]any)
+	locations := make([]map[string]any, 0, len(entries))
+	for _, entry := range entries {
+		location, ok := entry.(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid filer configuration location: %v", entry)
+		}
+		locations = append(locations, location)
+	}
+	return document, locations, nil
+}
+
+func renderFilerConf(document map[string]any, locations []map[string]any) ([]byte, error) {
+	entries := make([]any, 0, len(locations))
+	for _, location := range locations {
+		entries = append(entries, location)
+	}
+	document["locations"] = entries
+
+	out, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func filerConfValue(location map[string]any, key string) any {
+	if value, ok := location[key]; ok {
+		return value
+	}
+	return location[filerPathConfigKeys[key]]
+}
+
+func filerConfString(location map[string]any, key string) string {
+	value, _ := filerConfValue(location, key).(string)
+	return value
+}
+
+func filerConfBool(location map[string]any, key string) bool {
+	value, _ := filerConfValue(location, key).(bool)
+	return value
+}
+
+// filerConfInt accepts numbers and, as protobuf JSON writes 64-bit integers,
+// numeric strings.
+func filerConfInt(location map[string]any, key string) int64 {
+	switch value := filerConfValue(location, key).(type) {
+	case float64:
+		return int64(value)
+	case string:
+		parsed, _ := strconv.ParseInt(value, 10, 64)
+		return parsed
+	}
+	return 0
+}
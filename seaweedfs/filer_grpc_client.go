@@ -0,0 +1,253 @@
+package seaweedfs
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// filerGRPCClient talks to the SeaweedFS filer gRPC API for entry attributes
+// the filer HTTP API does not expose, such as bucket quotas.
+//
+// Messages are encoded by hand with protowire instead of generated filer_pb
+// types. Entries are edited on their wire form, so fields the provider does
+// not know about are written back unchanged.
+type filerGRPCClient struct {
+	conn *grpc.ClientConn
+}
+
+// Methods and field numbers from SeaweedFS weed/pb/filer.proto.
+const (
+	filerLookupDirectoryEntryMethod = "/filer_pb.SeaweedFiler/LookupDirectoryEntry"
+	filerUpdateEntryMethod          = "/filer_pb.SeaweedFiler/UpdateEntry"
+
+	filerRequestDirectoryField = 1 // LookupDirectoryEntryRequest and UpdateEntryRequest
+	filerRequestNameField      = 2 // LookupDirectoryEntryRequest
+	filerRequestEntryField     = 2 // UpdateEntryRequest
+	filerResponseEntryField    = 1 // LookupDirectoryEntryResponse
+	filerEntryQuotaField       = 11
+)
+
+// filerBucketsDirectory is the filer directory holding one entry per bucket.
+const filerBucketsDirectory = "/buckets"
+
+// filerGRPCPortOffset is the SeaweedFS default distance between a server's
+// HTTP and gRPC ports, for example 8888 and 18888 for the filer.
+const filerGRPCPortOffset = 10000
+
+var errFilerEntryNotFound = errors.New("filer entry not found")
+
+// rawCodec passes already encoded protobuf messages through gRPC.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("raw codec: unexpected message type %T", v)
+	}
+	return *b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("raw codec: unexpected message type %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// newFilerGRPCClient creates a client for the filer gRPC address host:port.
+// SeaweedFS secures gRPC with mutual TLS, so tlsConfig is only used when it
+// carries a client certificate; otherwise the connection is plaintext. The
+// connection is established lazily on the first call.
+func newFilerGRPCClient(address string, tlsConfig *tls.Config) (*filerGRPCClient, error) {
+	if address == "" {
+		return nil, errors.New("filer gRPC address is required")
+	}
+
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil && len(tlsConfig.Certificates) > 0 {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(rawCodec{})),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("filer gRPC client: %w", err)
+	}
+	return &filerGRPCClient{conn: conn}, nil
+}
+
+// filerGRPCAddress derives the filer gRPC address from the filer HTTP
+// endpoint using the SeaweedFS default port offset.
+func filerGRPCAddress(filerEndpoint string) (string, error) {
+	u, err := url.Parse(filerEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("filer endpoint: %w", err)
+	}
+	port := u.Port()
+	if port == "" {
+		return "", fmt.Errorf("filer endpoint %q has no port to derive the gRPC port from; set filer_grpc_endpoint", filerEndpoint)
+	}
+	httpPort, err := strconv.Atoi(port)
+	if err != nil {
+		return "", fmt.Errorf("filer endpoint port: %w", err)
+	}
+	return net.JoinHostPort(u.Hostname(), strconv.Itoa(httpPort+filerGRPCPortOffset)), nil
+}
+
+// BucketQuota returns the quota stored in the bucket's filer entry. A
+// positive value is an enforced limit in bytes, a negative value a disabled
+// limit and zero means no quota.
+func (c *filerGRPCClient) BucketQuota(ctx context.Context, bucket string) (int64, error) {
+	entry, err := c.lookupEntry(ctx, filerBucketsDirectory, bucket)
+	if err != nil {
+		return 0, err
+	}
+	return entryQuota(entry)
+}
+
+// SetBucketQuota writes quota into the bucket's filer entry, keeping every
+// other field of the entry.
+func (c *filerGRPCClient) SetBucketQuota(ctx context.Context, bucket string, quota int64) error {
+	entry, err := c.lookupEntry(ctx, filerBucketsDirectory, bucket)
+	if err != nil {
+		return err
+	}
+	entry, err = setEntryQuota(entry, quota)
+	if err != nil {
+		return err
+	}
+
+	var req []byte
+	req = protowire.AppendTag(req, filerRequestDirectoryField, protowire.BytesType)
+	req = protowire.AppendString(req, filerBucketsDirectory)
+	req = protowire.AppendTag(req, filerRequestEntryField, protowire.BytesType)
+	req = protowire.AppendBytes(req, entry)
+
+	var resp []byte
+	if err := c.conn.Invoke(ctx, filerUpdateEntryMethod, &req, &resp); err != nil {
+		return fmt.Errorf("update filer entry %s/%s: %w", filerBucketsDirectory, bucket, err)
+	}
+	return nil
+}
+
+// lookupEntry returns the encoded Entry message of directory/name.
+func (c *filerGRPCClient) lookupEntry(ctx context.Context, directory, name string) ([]byte, error) {
+	var req []byte
+	req = protowire.AppendTag(req, filerRequestDirectoryField, protowire.BytesType)
+	req = protowire.AppendString(req, directory)
+	req = protowire.AppendTag(req, filerRequestNameField, protowire.BytesType)
+	req = protowire.AppendString(req, name)
+
+	var resp []byte
+	if err := c.conn.Invoke(ctx, filerLookupDirectoryEntryMethod, &req, &resp); err != nil {
+		// The filer reports missing entries as an error carrying the text of
+		// filer_pb.ErrNotFound rather than a NotFound status code.
+		if strings.Contains(err.Error(), "no entry is found") {
+			return nil, fmt.Errorf("%s/%s: %w", directory, name, errFilerEntryNotFound)
+		}
+		return nil, fmt.Errorf("look up filer entry %s/%s: %w", directory, name, err)
+	}
+
+	var entry []byte
+	found := false
+	err := walkProtoFields(resp, func(num protowire.Number, typ protowire.Type, field []byte) error {
+		if num == filerResponseEntryField && typ == protowire.BytesType {
+			value, n := protowire.ConsumeBytes(field)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			entry, found = value, true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decode filer entry %s/%s: %w", directory, name, err)
+	}
+	if !found {
+		return nil, fmt.Errorf("%s/%s: %w", directory, name, errFilerEntryNotFound)
+	}
+	return entry, nil
+}
+
+func isFilerEntryNotFoundError(err error) bool {
+	return errors.Is(err, errFilerEntryNotFound)
+}
+
+// entryQuota returns the quota field of an encoded Entry message.
+func entryQuota(entry []byte) (int64, error) {
+	var quota int64
+	err := walkProtoFields(entry, func(num protowire.Number, typ protowire.Type, field []byte) error {
+		if num == filerEntryQuotaField && typ == protowire.VarintType {
+			value, n := protowire.ConsumeVarint(field)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			quota = int64(value)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("decode filer entry: %w", err)
+	}
+	return quota, nil
+}
+
+// setEntryQuota returns entry with its quota field replaced by quota. Zero
+// is the proto3 default and is encoded by leaving the field out.
+func setEntryQuota(entry []byte, quota int64) ([]byte, error) {
+	out := make([]byte, 0, len(entry)+protowire.SizeTag(filerEntryQuotaField)+protowire.SizeVarint(uint64(quota)))
+	err := walkProtoFields(entry, func(num protowire.Number, typ protowire.Type, field []byte) error {
+		if num != filerEntryQuotaField {
+			out = protowire.AppendTag(out, num, typ)
+			out = append(out, field...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decode filer entry: %w", err)
+	}
+	if quota != 0 {
+		out = protowire.AppendTag(out, filerEntryQuotaField, protowire.VarintType)
+		out = protowire.AppendVarint(out, uint64(quota))
+	}
+	return out, nil
+}
+
+// walkProtoFields calls fn for each field of an encoded message with the
+// field's value bytes, excluding its tag.
+func walkProtoFields(msg []byte, fn func(num protowire.Number, typ protowire.Type, field []byte) error) error {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+		n = protowire.ConsumeFieldValue(num, typ, msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		if err := fn(num, typ, msg[:n]); err != nil {
+			return err
+		}
+		msg = msg[n:]
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+package seaweedfs
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type masterClient struct {
+	endpoint string
+	http     *http.Client
+}
+
+// masterGrowRequest leaves empty fields to the master defaults.
+type masterGrowRequest struct {
+	Collection  string
+	Replication string
+	TTL         string
+	DiskType    string
+	Count       int64
+	DataCenter  string
+	Rack        string
+}
+
+// masterTopology is GET /dir/status. Fields differ between SeaweedFS versions
+// and numbers may arrive as strings.
+type masterTopology struct {
+	Topology masterTopologyInfo `json:"Topology"`
+}
+
+type masterTopologyInfo struct {
+	Max         masterInt            `json:"Max"`
+	Free        masterInt            `json:"Free"`
+	DataCenters []masterDataCenter   `json:"DataCenters"`
+	Layouts     []masterVolumeLayout `json:"Layouts"`
+}
+
+type masterDataCenter struct {
+	ID    string       `json:"Id"`
+	Racks []masterRack `json:"Racks"`
+}
+
+type masterRack struct {
+	ID        string           `json:"Id"`
+	DataNodes []masterDataNode `json:"DataNodes"`
+}
+
+type masterDataNode struct {
+	URL       string                    `json:"Url"`
+	PublicURL string                    `json:"PublicUrl"`
+	Volumes   masterInt                 `json:"Volumes"`
+	EcShards  masterInt                 `json:"EcShards"`
+	Max       masterInt                 `json:"Max"`
+	DiskInfos map[string]masterDiskInfo `json:"DiskInfos"`
+}
+
+type masterDiskInfo struct {
+	Type            string
+	MaxVolumeCount  int64
+	VolumeCount     int64
+	FreeVolumeCount int64
+}
+
+// UnmarshalJSON accepts camelCase and snake_case keys, as the disk info is
+// serialized from protobuf with either naming depending on the version.
+func (d *masterDiskInfo) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	fields := map[string]json.RawMessage{}
+	for key, value := range raw {
+		fields[strings.ToLower(strings.ReplaceAll(key, "_", ""))] = value
+	}
+	_ = json.Unmarshal(fields["type"], &d.Type)
+	for key, target := range map[string]*int64{
+		"maxvolumecount":  &d.MaxVolumeCount,
+		"volumecount":     &d.VolumeCount,
+		"freevolumecount": &d.FreeVolumeCount,
+	} {
+		var value masterInt
+		if err := value.UnmarshalJSON(fields[key]); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		*target = int64(value)
+	}
+	return nil
+}
+
+type masterVolumeLayout struct {
+	Collection  string  `json:"collection"`
+	Replication string  `json:"replication"`
+	TTL         string  `json:"ttl"`
+	DiskType    string  `json:"diskType"`
+	Writables   []int64 `json:"writables"`
+}
+
+type masterClusterStatus struct {
+	IsLeader bool
+	Leader   string
+	Peers    []string
+}
+
+// masterInt is an integer the master may encode as a number, a string or null.
+type masterInt int64
+
+func (i *masterInt) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(strings.TrimSpace(string(data)), `"`)
+	if text == "" || text == "null" {
+		*i = 0
+		return nil
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %s", data)
+	}
+	*i = masterInt(value)
+	return nil
+}
+
+type masterError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e masterError) Error() string {
+	return fmt.Sprintf("master HTTP%d: %s", e.StatusCode, e.Message)
+}
+
+func newMasterClient(endpoint string, tlsConfig *tls.Config) (*masterClient, error) {
+	if endpoint == "" {
+		return nil, errors.New("master endpoint is required")
+	}
+
+	return &masterClient{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		http: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			// Growing volumes can take minutes for larger counts.
+			Timeout: 5 * time.Minute,
+		},
+	}, nil
+}
+
+func (c *masterClient) GrowVolumes(ctx context.Context, req masterGrowRequest) (int64, error) {
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"collection":  req.Collection,
+		"replication": req.Replication,
+		"ttl":         req.TTL,
+		"diskType":    req.DiskType,
+		"dataCenter":  req.DataCenter,
+		"rack":        req.Rack,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	if req.Count > 0 {
+		query.Set("count", strconv.FormatInt(req.Count, 10))
+	}
+
+	data, err := c.do(ctx, "/vol/grow", query)
+	if err != nil {
+		return 0, err
+	}
+
+	var out struct {
+		Count int64 `json:"count"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return 0, fmt.Errorf("decode volume grow response: %w", err)
+	}
+	return out.Count, nil
+}
+
+func (c *masterClient) DeleteCollection(ctx context.Context, name string) error {
+	_, err := c.do(ctx, "/col/delete", url.Values{"collection": {name}})
+	return err
+}
+
+func (c *masterClient) DirStatus(ctx context.Context) (masterTopology, error) {
+	data, err := c.do(ctx, "/dir/status", nil)
+	if err != nil {
+		return masterTopology{}, err
+	}
+
+	var out masterTopology
+	if err := json.Unmarshal(data, &out); err != nil {
+		return masterTopology{}, fmt.Errorf("decode cluster topology: %w", err)
+	}
+	return out, nil
+}
+
+func (c *masterClient) ClusterStatus(ctx context.Context) (masterClusterStatus, error) {
+	data, err := c.do(ctx, "/cluster/status", nil)
+	if err != nil {
+		return masterClusterStatus{}, err
+	}
+
+	var raw struct {
+		IsLeader bool            `json:"IsLeader"`
+		Leader   string          `json:"Leader"`
+		Peers    json.RawMessage `json:"Peers"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return masterClusterStatus{}, fmt.Errorf("decode cluster status: %w", err)
+	}
+
+	out := masterClusterStatus{IsLeader: raw.IsLeader, Leader: raw.Leader}
+	// Older versions return Peers as a map keyed by address.
+	var peerList []string
+	var peerMap map[string]json.RawMessage
+	switch {
+	case json.Unmarshal(raw.Peers, &peerList) == nil:
+		out.Peers = peerList
+	case json.Unmarshal(raw.Peers, &peerMap) == nil:
+		for peer := range peerMap {
+			out.Peers = append(out.Peers, peer)
+		}
+	}
+	sort.Strings(out.Peers)
+	return out, nil
+}
+
+func (c *masterClient) CollectionExists(ctx context.Context, name string) (bool, error) {
+	status, err := c.DirStatus(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, layout := range status.Topology.Layouts {
+		if layout.Collection == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// WritableVolumeCount matches empty settings of req against any layout, as the
+// master fills in its defaults. /dir/status only lists writable volumes.
+func (c *masterClient) WritableVolumeCount(ctx context.Context, req masterGrowRequest) (int64, error) {
+	status, err := c.DirStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for _, layout := range status.Topology.Layouts {
+		if layout.Collection != req.Collection ||
+			(req.Replication != "" && layout.Replication != req.Replication) ||
+			(req.TTL != "" && layout.TTL != req.TTL) ||
+			(req.DiskType != "" && normalizeDiskType(layout.DiskType) != normalizeDiskType(req.DiskType)) {
+			continue
+		}
+		count += int64(len(layout.Writables))
+	}
+	return count, nil
+}
+
+// EnsureVolumes grows the volumes missing to reach req.Count, or the master's
+// default number when req.Count is zero and there are none yet.
+func (c *masterClient) EnsureVolumes(ctx context.Context, req masterGrowRequest) (int64, error) {
+	existing, err := c.WritableVolumeCount(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case req.Count == 0 && existing > 0:
+		return 0, nil
+	case req.Count > 0 && existing >= req.Count:
+		return 0, nil
+	case req.Count > 0:
+		req.Count -= existing
+	}
+	return c.GrowVolumes(ctx, req)
+}
+
+// normalizeDiskType names the empty disk type of default volumes.
+func normalizeDiskType(diskType string) string {
+	if diskType == "" {
+		return defaultDiskType
+	}
+	return diskType
+}
+
+func (c *masterClient) do(ctx context.Context, endpointPath string, query url.Values) ([]byte, error) {
+	requestURL := c.endpoint + endpointPath
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	// The master reports failures as {"error": "..."}, in some handlers with
+	// a success status code.
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	_ = json.Unmarshal(data, &apiErr)
+	if resp.StatusCode >= 400 || apiErr.Error != "" {
+		message := apiErr.Error
+		if message == "" {
+			message = strings.TrimSpace(string(data))
+		}
+		return nil, masterError{StatusCode: resp.StatusCode, Message: message}
+	}
+	return data, nil
+}
+
+func isMasterCollectionNotFoundError(err error) bool {
+	var apiErr masterError
+	return errors.As(err, &apiErr) && strings.Contains(apiErr.Message, "does not exist")
+}
@@ -0,0 +1,270 @@
+package seaweedfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// newFakeMaster tracks collections the way the master HTTP API reports them:
+// /vol/grow adds writable volumes to a layout, /col/delete removes the
+// layouts of a collection and /dir/status lists them.
+func newFakeMaster(t *testing.T) (*httptest.Server, *[]url.Values) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var grows []url.Values
+	// collection -> replication -> writable volume count
+	collections := map[string]map[string]int{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/vol/grow":
+			if query.Get("replication") == "999" {
+				w.WriteHeader(http.StatusNotAcceptable)
+				_, _ = w.Write([]byte(`{"error":"cannot grow volume group! Not enough data node found!"}`))
+				return
+			}
+			grows = append(grows, query)
+			count, replication := 2, firstNonEmpty(query.Get("replication"), "000")
+			if query.Get("count") != "" {
+				count, _ = strconv.Atoi(query.Get("count"))
+			}
+			name := query.Get("collection")
+			if collections[name] == nil {
+				collections[name] = map[string]int{}
+			}
+			collections[name][replication] += count
+			_, _ = fmt.Fprintf(w, `{"count":%d}`, count)
+		case "/col/delete":
+			name := query.Get("collection")
+			if collections[name] == nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"collection ` + name + ` does not exist"}`))
+				return
+			}
+			delete(collections, name)
+			w.WriteHeader(http.StatusNoContent)
+		case "/dir/status":
+			layouts := []map[string]any{}
+			for name, replications := range collections {
+				for replication, count := range replications {
+					writables := make([]int, count)
+					for i := range writables {
+						writables[i] = i + 1
+					}
+					layouts = append(layouts, map[string]any{"collection": name, "replication": replication, "ttl": "", "writables": writables})
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"Topology": map[string]any{"Max": 10, "Free": 8, "Layouts": layouts}})
+		case "/cluster/status":
+			_, _ = w.Write([]byte(`{"IsLeader":true,"Leader":"master-0:9333","Peers":["master-2:9333","master-1:9333"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &grows
+}
+
+func TestMasterClientCollections(t *testing.T) {
+	t.Parallel()
+
+	srv, grows := newFakeMaster(t)
+	client, err := newMasterClient(srv.URL+"/", nil)
+	if err != nil {
+		t.Fatalf("new master client: %v", err)
+	}
+
+	ctx := context.Background()
+	exists, err := client.CollectionExists(ctx, "tenant-a")
+	if err != nil || exists {
+		t.Fatalf("expected missing collection, got exists=%v err=%v", exists, err)
+	}
+
+	count, err := client.GrowVolumes(ctx, masterGrowRequest{Collection: "tenant-a", Replication: "001", Count: 2, DataCenter: "dc1", Rack: "rack1"})
+	if err != nil {
+		t.Fatalf("grow volumes: %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("unexpected grown volume count: %d", count)
+	}
+	want := url.Values{"collection": {"tenant-a"}, "replication": {"001"}, "count": {"2"}, "dataCenter": {"dc1"}, "rack": {"rack1"}}
+	if !reflect.DeepEqual((*grows)[0], want) {
+		t.Fatalf("unexpected grow query:\n got: %v\nwant: %v", (*grows)[0], want)
+	}
+
+	exists, err = client.CollectionExists(ctx, "tenant-a")
+	if err != nil || !exists {
+		t.Fatalf("expected collection after grow, got exists=%v err=%v", exists, err)
+	}
+
+	if _, err := client.GrowVolumes(ctx, masterGrowRequest{Collection: "tenant-a", Replication: "999"}); err == nil {
+		t.Fatal("expected grow error")
+	} else if err.Error() != "master HTTP406: cannot grow volume group! Not enough data node found!" {
+		t.Fatalf("unexpected grow error: %v", err)
+	}
+
+	if err := client.DeleteCollection(ctx, "tenant-a"); err != nil {
+		t.Fatalf("delete collection: %v", err)
+	}
+	if err := client.DeleteCollection(ctx, "tenant-a"); !isMasterCollectionNotFoundError(err) {
+		t.Fatalf("expected collection not found error, got: %v", err)
+	}
+
+	if _, err := newMasterClient("", nil); err == nil {
+		t.Fatal("expected an error for an empty endpoint")
+	}
+}
+
+func TestMasterClientEnsureVolumes(t *testing.T) {
+	t.Parallel()
+
+	srv, grows := newFakeMaster(t)
+	client, err := newMasterClient(srv.URL, nil)
+	if err != nil {
+		t.Fatalf("new master client: %v", err)
+	}
+
+	ctx := context.Background()
+	req := masterGrowRequest{Collection: "tenant-b", Replication: "001", Count: 10}
+
+	steps := []struct {
+		name      string
+		count     int64
+		wantGrown int64
+	}{
+		{name: "create", count: 10, wantGrown: 10},
+		{name: "unchanged", count: 10},
+		{name: "lowered", count: 5},
+		{name: "raised", count: 12, wantGrown: 2},
+	}
+	for _, step := range steps {
+		before := len(*grows)
+		req.Count = step.count
+		grown, err := client.EnsureVolumes(ctx, req)
+		if err != nil {
+			t.Fatalf("%s: ensure volumes: %v", step.name, err)
+		}
+		if grown != step.wantGrown {
+			t.Fatalf("%s: expected %d grown volumes, got %d", step.name, step.wantGrown, grown)
+		}
+		if step.wantGrown == 0 {
+			if len(*grows) != before {
+				t.Fatalf("%s: expected no growth, got %v", step.name, (*grows)[before:])
+			}
+			continue
+		}
+		if got := (*grows)[len(*grows)-1].Get("count"); len(*grows) != before+1 || got != strconv.FormatInt(step.wantGrown, 10) {
+			t.Fatalf("%s: expected one growth of %d volumes, got %v", step.name, step.wantGrown, (*grows)[before:])
+		}
+	}
+
+	// Volumes of another replication do not count towards the layout.
+	other, err := client.WritableVolumeCount(ctx, masterGrowRequest{Collection: "tenant-b", Replication: "010"})
+	if err != nil || other != 0 {
+		t.Fatalf("expected no volumes with replication 010, got %d, %v", other, err)
+	}
+	total, err := client.WritableVolumeCount(ctx, masterGrowRequest{Collection: "tenant-b"})
+	if err != nil || total != 12 {
+		t.Fatalf("expected 12 volumes in any layout, got %d, %v", total, err)
+	}
+
+	// Without a count the master default is grown only for an empty layout.
+	if grown, err := client.EnsureVolumes(ctx, masterGrowRequest{Collection: "tenant-b"}); err != nil || grown != 0 {
+		t.Fatalf("expected no growth without count, got %d, %v", grown, err)
+	}
+	if grown, err := client.EnsureVolumes(ctx, masterGrowRequest{Collection: "tenant-c"}); err != nil || grown != 2 {
+		t.Fatalf("expected default growth for a new collection, got %d, %v", grown, err)
+	}
+}
+
+func TestSummarizeCluster(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newFakeMaster(t)
+	client, err := newMasterClient(srv.URL, nil)
+	if err != nil {
+		t.Fatalf("new master client: %v", err)
+	}
+	status, err := client.ClusterStatus(context.Background())
+	if err != nil {
+		t.Fatalf("cluster status: %v", err)
+	}
+	if want := (masterClusterStatus{IsLeader: true, Leader: "master-0:9333", Peers: []string{"master-1:9333", "master-2:9333"}}); !reflect.DeepEqual(status, want) {
+		t.Fatalf("unexpected cluster status:\n got: %+v\nwant: %+v", status, want)
+	}
+
+	// rack1 has a node reporting node totals only, as older versions do;
+	// rack2 has a node with per-disk-type capacity in snake_case and a
+	// numeric string.
+	var topology masterTopology
+	if err := json.Unmarshal([]byte(`{
+  "Topology": {
+    "Max": 99,
+    "Free": 99,
+    "DataCenters": [
+      {
+        "Id": "dc1",
+        "Racks": [
+          {"Id": "rack1", "DataNodes": [{"Url": "vol-1:8080", "PublicUrl": "vol-1.example.com:8080", "Volumes": 3, "EcShards": 2, "Max": 8, "VolumeIds": " 1-3"}]},
+          {"Id": "rack2", "DataNodes": [{"Url": "vol-2:8080", "Volumes": 5, "Max": "12", "DiskInfos": {
+            "": {"type": "", "max_volume_count": 4, "volume_count": 4},
+            "ssd": {"type": "ssd", "maxVolumeCount": "8", "volumeCount": 1, "freeVolumeCount": 7}
+          }}]}
+        ]
+      }
+    ],
+    "Layouts": [{"collection": "", "replication": "000", "ttl": "", "writables": [1, 2, 3]}]
+  },
+  "Version": "30GB 3.80"
+}`), &topology); err != nil {
+		t.Fatalf("decode topology: %v", err)
+	}
+
+	summary := summarizeCluster(topology, status)
+	if summary.Leader != "master-0:9333" || len(summary.Peers) != 2 {
+		t.Fatalf("unexpected leader or peers: %+v", summary)
+	}
+	if want := (clusterCapacity{Max: 20, Volumes: 8, Free: 12}); summary.clusterCapacity != want {
+		t.Fatalf("unexpected cluster capacity:\n got: %+v\nwant: %+v", summary.clusterCapacity, want)
+	}
+
+	racks := summary.DataCenters[0].Racks
+	if len(racks) != 2 || racks[0].ID != "rack1" || racks[1].ID != "rack2" {
+		t.Fatalf("unexpected racks: %+v", racks)
+	}
+	if want := (clusterCapacity{Max: 8, Volumes: 3, Free: 5}); racks[0].clusterCapacity != want {
+		t.Fatalf("unexpected rack1 capacity:\n got: %+v\nwant: %+v", racks[0].clusterCapacity, want)
+	}
+	if node := racks[0].DataNodes[0]; node.EcShards != 2 || node.PublicURL != "vol-1.example.com:8080" {
+		t.Fatalf("unexpected data node: %+v", node)
+	}
+	if want := (clusterCapacity{Max: 12, Volumes: 5, Free: 7}); racks[1].clusterCapacity != want {
+		t.Fatalf("unexpected rack2 capacity:\n got: %+v\nwant: %+v", racks[1].clusterCapacity, want)
+	}
+
+	wantDisks := []clusterDiskTypeSummary{
+		{DiskType: "hdd", clusterCapacity: clusterCapacity{Max: 12, Volumes: 7, Free: 5}},
+		{DiskType: "ssd", clusterCapacity: clusterCapacity{Max: 8, Volumes: 1, Free: 7}},
+	}
+	if !reflect.DeepEqual(summary.DiskTypes, wantDisks) {
+		t.Fatalf("unexpected disk types:\n got: %+v\nwant: %+v", summary.DiskTypes, wantDisks)
+	}
+
+	empty := summarizeCluster(masterTopology{Topology: masterTopologyInfo{Max: 10, Free: 4}}, masterClusterStatus{})
+	if want := (clusterCapacity{Max: 10, Volumes: 6, Free: 4}); empty.clusterCapacity != want {
+		t.Fatalf("unexpected capacity without data centers:\n got: %+v\nwant: %+v", empty.clusterCapacity, want)
+	}
+}
@@ -2,6 +2,10 @@ package seaweedfs
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 )
 
@@ -20,6 +24,12 @@ func normalizeJSONString(raw string) (string, error) {
 }
 
 func policiesSemanticallyEqual(a string, b string) bool {
+	ca, errA := canonicalizePolicyJSON(a)
+	cb, errB := canonicalizePolicyJSON(b)
+	if errA == nil && errB == nil {
+		return ca == cb
+	}
+
 	na, errA := normalizeJSONString(a)
 	nb, errB := normalizeJSONString(b)
 	if errA == nil && errB == nil {
@@ -28,3 +38,649 @@ func policiesSemanticallyEqual(a string, b string) bool {
 
 	return strings.TrimSpace(a) == strings.TrimSpace(b)
 }
+
+// policyStateFromRemote returns the policy document to keep in state after
+// reading remote. The document in state is kept as written while it matches
+// remote in content, so whitespace and key order never show up as drift;
+// otherwise remote is returned in normalized form.
+func policyStateFromRemote(state string, remote string) string {
+	if state != "" && policiesSemanticallyEqual(state, remote) {
+		return state
+	}
+	if normalized, err := normalizeJSONString(remote); err == nil {
+		return normalized
+	}
+	return remote
+}
+
+// canonicalizePolicyJSON renders an IAM policy document in a form where
+// differences that IAM does not treat as meaningful disappear: a single
+// statement or value versus a one-element list, the order of statements and
+// of Action/Resource/Principal/Condition values, and duplicates in those
+// lists. Keys outside the IAM grammar are kept as they are.
+func canonicalizePolicyJSON(raw string) (string, error) {
+	var document map[string]any
+	if err := json.Unmarshal([]byte(raw), &document); err != nil {
+		return "", err
+	}
+
+	if statements, ok := document["Statement"]; ok {
+		list, ok := statements.([]any)
+		if !ok {
+			list = []any{statements}
+		}
+
+		canonical := make([]any, 0, len(list))
+		for _, statement := range list {
+			if fields, ok := statement.(map[string]any); ok {
+				statement = canonicalPolicyStatement(fields)
+			}
+			canonical = append(canonical, statement)
+		}
+		// Statement always renders as a list, even with a single entry.
+		sorted := canonicalPolicyList(canonical)
+		if _, ok := sorted.([]any); !ok {
+			sorted = []any{sorted}
+		}
+		document["Statement"] = sorted
+	}
+
+	out, err := json.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func canonicalPolicyStatement(statement map[string]any) map[string]any {
+	for _, key := range []string{"Action", "NotAction", "Resource", "NotResource"} {
+		if value, ok := statement[key]; ok {
+			statement[key] = canonicalPolicyList(value)
+		}
+	}
+
+	// Principal is either "*" or a map of principal type to one or more
+	// identifiers; Condition maps operator -> key -> one or more values.
+	for _, key := range []string{"Principal", "NotPrincipal"} {
+		if principals, ok := statement[key].(map[string]any); ok {
+			for principalType, identifiers := range principals {
+				principals[principalType] = canonicalPolicyList(identifiers)
+			}
+		}
+	}
+	if conditions, ok := statement["Condition"].(map[string]any); ok {
+		for _, operator := range conditions {
+			if keys, ok := operator.(map[string]any); ok {
+				for key, values := range keys {
+					keys[key] = canonicalPolicyList(values)
+				}
+			}
+		}
+	}
+	return statement
+}
+
+// canonicalPolicyList sorts and deduplicates a policy value list by its JSON
+// encoding. A list with one element collapses to that element and a scalar
+// is returned unchanged.
+func canonicalPolicyList(value any) any {
+	list, ok := value.([]any)
+	if !ok {
+		return value
+	}
+
+	encoded := map[string]any{}
+	for _, item := range list {
+		key, err := json.Marshal(item)
+		if err != nil {
+			return value
+		}
+		encoded[string(key)] = item
+	}
+
+	keys := make([]string, 0, len(encoded))
+	for key := range encoded {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if len(keys) == 1 {
+		return encoded[keys[0]]
+	}
+	out := make([]any, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, encoded[key])
+	}
+	return out
+}
+
+const defaultPolicyVersion = "2012-10-17"
+
+// policyDocument is the structured form of an IAM policy built from HCL.
+type policyDocument struct {
+	Version    string
+	ID         string
+	Statements []policyStatement
+}
+
+type policyStatement struct {
+	Sid           string
+	Effect        string
+	Actions       []string
+	NotActions    []string
+	Resources     []string
+	NotResources  []string
+	Principals    []policyPrincipal
+	NotPrincipals []policyPrincipal
+	Conditions    []policyCondition
+}
+
+type policyPrincipal struct {
+	Type        string
+	Identifiers []string
+}
+
+type policyCondition struct {
+	Test     string
+	Variable string
+	Values   []string
+}
+
+// renderPolicyDocument merges doc with the source and override documents and
+// returns compact JSON with sorted keys, so the result is already in the form
+// produced by normalizeJSONString.
+//
+// Statements from sources are combined first. Statements in doc replace source
+// statements with the same Sid, and statements from overrides replace any
+// earlier statement with the same Sid. Statements without a Sid are appended.
+func renderPolicyDocument(doc policyDocument, sources []string, overrides []string) (string, error) {
+	version := ""
+	id := ""
+	var statements []map[string]any
+
+	for i, source := range sources {
+		parsed, err := parsePolicyDocument(source)
+		if err != nil {
+			return "", fmt.Errorf("source_policy_documents[%d]: %w", i, err)
+		}
+		for _, statement := range parsed.statements {
+			if sid := statementSid(statement); sid != "" && findStatementBySid(statements, sid) >= 0 {
+				return "", fmt.Errorf("source_policy_documents[%d]: duplicate statement sid %q across source documents", i, sid)
+			}
+			statements = append(statements, statement)
+		}
+		version = firstNonEmpty(parsed.version, version)
+		id = firstNonEmpty(parsed.id, id)
+	}
+
+	seen := map[string]bool{}
+	own := make([]map[string]any, 0, len(doc.Statements))
+	for _, statement := range doc.Statements {
+		if statement.Sid != "" {
+			if seen[statement.Sid] {
+				return "", fmt.Errorf("duplicate statement sid %q", statement.Sid)
+			}
+			seen[statement.Sid] = true
+		}
+		own = append(own, renderPolicyStatement(statement))
+	}
+	statements = mergePolicyStatements(statements, own)
+	version = firstNonEmpty(doc.Version, version)
+	id = firstNonEmpty(doc.ID, id)
+
+	for i, override := range overrides {
+		parsed, err := parsePolicyDocument(override)
+		if err != nil {
+			return "", fmt.Errorf("override_policy_documents[%d]: %w", i, err)
+		}
+		statements = mergePolicyStatements(statements, parsed.statements)
+		version = firstNonEmpty(parsed.version, version)
+		id = firstNonEmpty(parsed.id, id)
+	}
+
+	rendered := map[string]any{
+		"Version":   firstNonEmpty(version, defaultPolicyVersion),
+		"Statement": statements,
+	}
+	if statements == nil {
+		rendered["Statement"] = []map[string]any{}
+	}
+	if id != "" {
+		rendered["Id"] = id
+	}
+
+	out, err := json.Marshal(rendered)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func renderPolicyStatement(statement policyStatement) map[string]any {
+	rendered := map[string]any{
+		"Effect": firstNonEmpty(statement.Effect, "Allow"),
+	}
+	if statement.Sid != "" {
+		rendered["Sid"] = statement.Sid
+	}
+	setPolicyValues(rendered, "Action", statement.Actions)
+	setPolicyValues(rendered, "NotAction", statement.NotActions)
+	setPolicyValues(rendered, "Resource", statement.Resources)
+	setPolicyValues(rendered, "NotResource", statement.NotResources)
+	if principals := renderPolicyPrincipals(statement.Principals); principals != nil {
+		rendered["Principal"] = principals
+	}
+	if principals := renderPolicyPrincipals(statement.NotPrincipals); principals != nil {
+		rendered["NotPrincipal"] = principals
+	}
+
+	if len(statement.Conditions) > 0 {
+		conditions := map[string]map[string]any{}
+		for _, condition := range statement.Conditions {
+			if conditions[condition.Test] == nil {
+				conditions[condition.Test] = map[string]any{}
+			}
+			conditions[condition.Test][condition.Variable] = policyValue(condition.Values)
+		}
+		rendered["Condition"] = conditions
+	}
+	return rendered
+}
+
+// renderPolicyPrincipals returns "*" for a single wildcard principal and a
+// type-to-identifiers map otherwise.
+func renderPolicyPrincipals(principals []policyPrincipal) any {
+	if len(principals) == 0 {
+		return nil
+	}
+	if len(principals) == 1 && principals[0].Type == "*" {
+		return "*"
+	}
+
+	identifiers := map[string][]string{}
+	for _, principal := range principals {
+		identifiers[principal.Type] = append(identifiers[principal.Type], principal.Identifiers...)
+	}
+	rendered := make(map[string]any, len(identifiers))
+	for principalType, values := range identifiers {
+		rendered[principalType] = policyValue(values)
+	}
+	return rendered
+}
+
+func setPolicyValues(statement map[string]any, key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	statement[key] = policyValue(values)
+}
+
+// policyValue renders a single value as a string and several values as a
+// sorted list.
+func policyValue(values []string) any {
+	if len(values) == 1 {
+		return values[0]
+	}
+	sorted := slices.Clone(values)
+	sort.Strings(sorted)
+	return sorted
+}
+
+type parsedPolicyDocument struct {
+	version    string
+	id         string
+	statements []map[string]any
+}
+
+func parsePolicyDocument(raw string) (parsedPolicyDocument, error) {
+	var document struct {
+		Version   string          `json:"Version"`
+		ID        string          `json:"Id"`
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal([]byte(raw), &document); err != nil {
+		return parsedPolicyDocument{}, fmt.Errorf("invalid policy JSON: %w", err)
+	}
+
+	parsed := parsedPolicyDocument{version: document.Version, id: document.ID}
+	if len(document.Statement) == 0 {
+		return parsed, nil
+	}
+
+	// Statement may be a single object or a list of objects.
+	var single map[string]any
+	if err := json.Unmarshal(document.Statement, &single); err == nil {
+		parsed.statements = []map[string]any{single}
+		return parsed, nil
+	}
+	if err := json.Unmarshal(document.Statement, &parsed.statements); err != nil {
+		return parsedPolicyDocument{}, fmt.Errorf("invalid policy Statement: %w", err)
+	}
+	return parsed, nil
+}
+
+func mergePolicyStatements(base []map[string]any, overrides []map[string]any) []map[string]any {
+	for _, statement := range overrides {
+		if index := findStatementBySid(base, statementSid(statement)); index >= 0 {
+			base[index] = statement
+			continue
+		}
+		base = append(base, statement)
+	}
+	return base
+}
+
+func findStatementBySid(statements []map[string]any, sid string) int {
+	if sid == "" {
+		return -1
+	}
+	for i, statement := range statements {
+		if statementSid(statement) == sid {
+			return i
+		}
+	}
+	return -1
+}
+
+func statementSid(statement map[string]any) string {
+	sid, _ := statement["Sid"].(string)
+	return sid
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// policyValidation collects problems found by validatePolicyDocument. Errors
+// describe syntactically invalid documents; warnings describe statements
+// that are valid IAM but have no effect on SeaweedFS. UnknownActions lists
+// S3 actions missing from knownS3Actions, which are likely typos but may
+// also be valid actions the list does not cover.
+type policyValidation struct {
+	Errors         []string
+	Warnings       []string
+	UnknownActions []string
+}
+
+// seaweedfsEnforcedActions are the S3 actions that SeaweedFS maps to its own
+// Admin/Read/Write/List/Tagging permissions in identity policies. Any other
+// action is accepted by the server and then silently ignored.
+var seaweedfsEnforcedActions = []string{
+	"*",
+	"DeleteBucket*",
+	"Get*",
+	"GetBucketAcl",
+	"List*",
+	"Put*",
+	"PutBucketAcl",
+	"Tagging*",
+}
+
+// knownS3Actions is the set of S3 IAM actions used to catch typos such as
+// s3:GetObjects. It is not exhaustive, so a miss is only a warning. IAM
+// action names are case-insensitive.
+var knownS3Actions = []string{
+	"AbortMultipartUpload",
+	"BypassGovernanceRetention",
+	"CreateBucket",
+	"DeleteBucket",
+	"DeleteBucketOwnershipControls",
+	"DeleteBucketPolicy",
+	"DeleteBucketWebsite",
+	"DeleteObject",
+	"DeleteObjectTagging",
+	"DeleteObjectVersion",
+	"DeleteObjectVersionTagging",
+	"GetAccelerateConfiguration",
+	"GetAnalyticsConfiguration",
+	"GetBucketAcl",
+	"GetBucketCORS",
+	"GetBucketLocation",
+	"GetBucketLogging",
+	"GetBucketNotification",
+	"GetBucketObjectLockConfiguration",
+	"GetBucketOwnershipControls",
+	"GetBucketPolicy",
+	"GetBucketPolicyStatus",
+	"GetBucketPublicAccessBlock",
+	"GetBucketRequestPayment",
+	"GetBucketTagging",
+	"GetBucketVersioning",
+	"GetBucketWebsite",
+	"GetEncryptionConfiguration",
+	"GetIntelligentTieringConfiguration",
+	"GetInventoryConfiguration",
+	"GetLifecycleConfiguration",
+	"GetMetricsConfiguration",
+	"GetObject",
+	"GetObjectAcl",
+	"GetObjectAttributes",
+	"GetObjectLegalHold",
+	"GetObjectRetention",
+	"GetObjectTagging",
+	"GetObjectVersion",
+	"GetObjectVersionAcl",
+	"GetObjectVersionAttributes",
+	"GetObjectVersionTagging",
+	"GetReplicationConfiguration",
+	"ListAllMyBuckets",
+	"ListBucket",
+	"ListBucketMultipartUploads",
+	"ListBucketVersions",
+	"ListMultipartUploadParts",
+	"PutAccelerateConfiguration",
+	"PutAnalyticsConfiguration",
+	"PutBucketAcl",
+	"PutBucketCORS",
+	"PutBucketLogging",
+	"PutBucketNotification",
+	"PutBucketObjectLockConfiguration",
+	"PutBucketOwnershipControls",
+	"PutBucketPolicy",
+	"PutBucketPublicAccessBlock",
+	"PutBucketRequestPayment",
+	"PutBucketTagging",
+	"PutBucketVersioning",
+	"PutBucketWebsite",
+	"PutEncryptionConfiguration",
+	"PutIntelligentTieringConfiguration",
+	"PutInventoryConfiguration",
+	"PutLifecycleConfiguration",
+	"PutMetricsConfiguration",
+	"PutObject",
+	"PutObjectAcl",
+	"PutObjectLegalHold",
+	"PutObjectRetention",
+	"PutObjectTagging",
+	"PutObjectVersionAcl",
+	"PutObjectVersionTagging",
+	"PutReplicationConfiguration",
+	"RestoreObject",
+}
+
+// validatePolicyDocument checks an identity policy against the IAM grammar
+// and the subset of actions SeaweedFS enforces.
+func validatePolicyDocument(raw string) policyValidation {
+	var result policyValidation
+
+	parsed, err := parsePolicyDocument(raw)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	if parsed.version != "" && parsed.version != defaultPolicyVersion && parsed.version != "2008-10-17" {
+		result.Errors = append(result.Errors, fmt.Sprintf("unsupported policy Version %q: use %q", parsed.version, defaultPolicyVersion))
+	}
+	if len(parsed.statements) == 0 {
+		result.Errors = append(result.Errors, "policy must contain at least one Statement")
+	}
+
+	var ignored []string
+	for i, statement := range parsed.statements {
+		label := fmt.Sprintf("statement %d", i+1)
+		if sid := statementSid(statement); sid != "" {
+			label = fmt.Sprintf("statement %q", sid)
+		}
+
+		if effect, _ := statement["Effect"].(string); effect != "Allow" && effect != "Deny" {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: Effect must be \"Allow\" or \"Deny\"", label))
+		}
+
+		actions, errs := policyStatementValues(statement, label, "Action", "NotAction")
+		result.Errors = append(result.Errors, errs...)
+		for _, action := range actions {
+			known, enforced, problem := classifyPolicyAction(action)
+			if problem != "" {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", label, problem))
+				continue
+			}
+			if !known {
+				result.UnknownActions = append(result.UnknownActions, fmt.Sprintf("%s: %s", label, unknownActionMessage(action)))
+				continue
+			}
+			if known && !enforced && !slices.Contains(ignored, action) {
+				ignored = append(ignored, action)
+			}
+		}
+
+		resources, errs := policyStatementValues(statement, label, "Resource", "NotResource")
+		result.Errors = append(result.Errors, errs...)
+		for _, resource := range resources {
+			if resource != "*" && !validPolicyARN(resource) {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: malformed resource ARN %q: expected arn:partition:service:region:account:resource", label, resource))
+			}
+		}
+	}
+
+	if len(ignored) > 0 {
+		enforced := make([]string, 0, len(seaweedfsEnforcedActions))
+		for _, action := range seaweedfsEnforcedActions {
+			enforced = append(enforced, "s3:"+action)
+		}
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"SeaweedFS ignores %s in identity policies; it only enforces %s.",
+			strings.Join(ignored, ", "),
+			strings.Join(enforced, ", "),
+		))
+	}
+	return result
+}
+
+// policyStatementValues returns the values of key or its Not variant, which
+// must be a string or a list of strings. Exactly one of the two must be set.
+func policyStatementValues(statement map[string]any, label string, key string, notKey string) ([]string, []string) {
+	value, hasKey := statement[key]
+	notValue, hasNotKey := statement[notKey]
+	switch {
+	case hasKey && hasNotKey:
+		return nil, []string{fmt.Sprintf("%s: %s and %s cannot both be set", label, key, notKey)}
+	case !hasKey && !hasNotKey:
+		return nil, []string{fmt.Sprintf("%s: one of %s or %s is required", label, key, notKey)}
+	case hasNotKey:
+		key, value = notKey, notValue
+	}
+
+	var values []string
+	switch typed := value.(type) {
+	case string:
+		values = []string{typed}
+	case []any:
+		for _, item := range typed {
+			text, ok := item.(string)
+			if !ok {
+				return nil, []string{fmt.Sprintf("%s: %s must be a string or a list of strings", label, key)}
+			}
+			values = append(values, text)
+		}
+	default:
+		return nil, []string{fmt.Sprintf("%s: %s must be a string or a list of strings", label, key)}
+	}
+	if len(values) == 0 {
+		return nil, []string{fmt.Sprintf("%s: %s must not be empty", label, key)}
+	}
+	return values, nil
+}
+
+// classifyPolicyAction reports whether an action names at least one known
+// action and whether SeaweedFS enforces it. problem is set for malformed
+// actions only.
+func classifyPolicyAction(action string) (known bool, enforced bool, problem string) {
+	if action == "*" {
+		return true, true, ""
+	}
+
+	service, name, ok := strings.Cut(action, ":")
+	if !ok || service == "" || name == "" {
+		return false, false, fmt.Sprintf("malformed action %q: expected service:Action", action)
+	}
+	if service != "s3" {
+		// Non-S3 actions are valid IAM but never evaluated by SeaweedFS.
+		return true, false, ""
+	}
+	if slices.Contains(seaweedfsEnforcedActions, name) {
+		return true, true, ""
+	}
+
+	pattern, err := regexp.Compile("(?i)^" + strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(name)) + "$")
+	if err != nil {
+		return false, false, fmt.Sprintf("malformed action %q", action)
+	}
+	for _, candidate := range knownS3Actions {
+		if pattern.MatchString(candidate) {
+			return true, false, ""
+		}
+	}
+	return false, false, ""
+}
+
+// unknownActionMessage describes an S3 action that is not in
+// knownS3Actions, suggesting the closest known action for likely typos.
+func unknownActionMessage(action string) string {
+	name := strings.TrimPrefix(action, "s3:")
+	if strings.ContainsAny(name, "*?") {
+		return fmt.Sprintf("action %q does not match any known S3 action", action)
+	}
+
+	suggestion, best := "", len(name)/3+1
+	for _, candidate := range knownS3Actions {
+		if distance := editDistance(strings.ToLower(name), strings.ToLower(candidate)); distance < best {
+			suggestion, best = candidate, distance
+		}
+	}
+	if suggestion != "" {
+		return fmt.Sprintf("unknown action %q, did you mean \"s3:%s\"?", action, suggestion)
+	}
+	return fmt.Sprintf("unknown action %q", action)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func validPolicyARN(value string) bool {
+	parts := strings.SplitN(value, ":", 6)
+	return len(parts) == 6 && parts[0] == "arn" && parts[1] != "" && parts[2] != "" && parts[5] != ""
+}
@@ -3,9 +3,12 @@ package seaweedfs
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -26,13 +29,40 @@ type seaweedfsProviderModel struct {
 	AccessKey types.String `tfsdk:"access_key"`
 	SecretKey types.String `tfsdk:"secret_key"`
 	Insecure  types.Bool   `tfsdk:"insecure"`
+
+	FilerEndpoint     types.String `tfsdk:"filer_endpoint"`
+	FilerGRPCEndpoint types.String `tfsdk:"filer_grpc_endpoint"`
+	MasterEndpoint    types.String `tfsdk:"master_endpoint"`
+
+	SharedCredentialsFile types.String `tfsdk:"shared_credentials_file"`
+	Profile               types.String `tfsdk:"profile"`
+
+	CACertFile types.String `tfsdk:"ca_cert_file"`
+	CACertPEM  types.String `tfsdk:"ca_cert_pem"`
+	ClientCert types.String `tfsdk:"client_cert"`
+	ClientKey  types.String `tfsdk:"client_key"`
+
+	AssumeRole *assumeRoleModel `tfsdk:"assume_role"`
+}
+
+type assumeRoleModel struct {
+	RoleARN     types.String `tfsdk:"role_arn"`
+	SessionName types.String `tfsdk:"session_name"`
+	Duration    types.String `tfsdk:"duration"`
 }
 
 type providerData struct {
-	client    *iamClient
-	iamWrite  sync.Mutex
-	lockMu    sync.Mutex
-	userLocks map[string]*sync.Mutex
+	client      *iamClient
+	filer       *filerClient
+	filerGRPC   *filerGRPCClient
+	master      *masterClient
+	iamWrite    sync.Mutex
+	lockMu      sync.Mutex
+	userLocks   map[string]*sync.Mutex
+	groupLocks  map[string]*sync.Mutex
+	policyLocks map[string]*sync.Mutex
+	roleLocks   map[string]*sync.Mutex
+	filerLocks  map[string]*sync.Mutex
 }
 
 func (d *providerData) withUserLock(userName string, fn func() error) error {
@@ -45,6 +75,85 @@ func (d *providerData) withUserLock(userName string, fn func() error) error {
 	return fn()
 }
 
+func (d *providerData) withGroupLock(groupName string, fn func() error) error {
+	d.iamWrite.Lock()
+	defer d.iamWrite.Unlock()
+
+	lock := d.getGroupLock(groupName)
+	lock.Lock()
+	defer lock.Unlock()
+	return fn()
+}
+
+func (d *providerData) withPolicyLock(policyName string, fn func() error) error {
+	d.iamWrite.Lock()
+	defer d.iamWrite.Unlock()
+
+	lock := d.getPolicyLock(policyName)
+	lock.Lock()
+	defer lock.Unlock()
+	return fn()
+}
+
+func (d *providerData) withRoleLock(roleName string, fn func() error) error {
+	d.iamWrite.Lock()
+	defer d.iamWrite.Unlock()
+
+	lock := d.getRoleLock(roleName)
+	lock.Lock()
+	defer lock.Unlock()
+	return fn()
+}
+
+// withFilerFileLock serializes read-modify-write cycles on a filer file that
+// several resources share.
+func (d *providerData) withFilerFileLock(filePath string, fn func() error) error {
+	lock := d.getNamedLock(&d.filerLocks, filePath)
+	lock.Lock()
+	defer lock.Unlock()
+	return fn()
+}
+
+// updateFilerFile runs a conflict-checked read-modify-write of a filer file
+// under the provider-wide lock for that file.
+func (d *providerData) updateFilerFile(ctx context.Context, resourceType string, filePath string, update func(current []byte) ([]byte, error)) error {
+	filer, err := d.requireFiler(resourceType)
+	if err != nil {
+		return err
+	}
+	return d.withFilerFileLock(filePath, func() error {
+		return filer.UpdateFile(ctx, filePath, update)
+	})
+}
+
+// requireFiler returns the filer client, or an error naming the resource when
+// filer_endpoint is not configured.
+func (d *providerData) requireFiler(resourceType string) (*filerClient, error) {
+	if d.filer == nil {
+		return nil, fmt.Errorf("%s requires filer_endpoint to be set in the provider configuration or via SEAWEEDFS_FILER_ENDPOINT", resourceType)
+	}
+	return d.filer, nil
+}
+
+// requireFilerGRPC returns the filer gRPC client, or an error naming the
+// resource when neither filer_grpc_endpoint nor a filer_endpoint to derive it
+// from is configured.
+func (d *providerData) requireFilerGRPC(resourceType string) (*filerGRPCClient, error) {
+	if d.filerGRPC == nil {
+		return nil, fmt.Errorf("%s requires filer_grpc_endpoint, or a filer_endpoint with an explicit port to derive it from, to be set in the provider configuration or via SEAWEEDFS_FILER_GRPC_ENDPOINT", resourceType)
+	}
+	return d.filerGRPC, nil
+}
+
+// requireMaster returns the master client, or an error naming the resource
+// when master_endpoint is not configured.
+func (d *providerData) requireMaster(resourceType string) (*masterClient, error) {
+	if d.master == nil {
+		return nil, fmt.Errorf("%s requires master_endpoint to be set in the provider configuration or via SEAWEEDFS_MASTER_ENDPOINT", resourceType)
+	}
+	return d.master, nil
+}
+
 func (d *providerData) getUserLock(userName string) *sync.Mutex {
 	d.lockMu.Lock()
 	defer d.lockMu.Unlock()
@@ -62,6 +171,35 @@ func (d *providerData) getUserLock(userName string) *sync.Mutex {
 	return lock
 }
 
+func (d *providerData) getGroupLock(groupName string) *sync.Mutex {
+	return d.getNamedLock(&d.groupLocks, groupName)
+}
+
+func (d *providerData) getPolicyLock(policyName string) *sync.Mutex {
+	return d.getNamedLock(&d.policyLocks, policyName)
+}
+
+func (d *providerData) getRoleLock(roleName string) *sync.Mutex {
+	return d.getNamedLock(&d.roleLocks, roleName)
+}
+
+func (d *providerData) getNamedLock(locks *map[string]*sync.Mutex, name string) *sync.Mutex {
+	d.lockMu.Lock()
+	defer d.lockMu.Unlock()
+
+	if *locks == nil {
+		*locks = map[string]*sync.Mutex{}
+	}
+
+	if lock, ok := (*locks)[name]; ok {
+		return lock
+	}
+
+	lock := &sync.Mutex{}
+	(*locks)[name] = lock
+	return lock
+}
+
 func (p *seaweedfsProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "seaweedfs"
 }
@@ -70,27 +208,83 @@ func (p *seaweedfsProvider) Schema(_ context.Context, _ provider.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				Required:    true,
-				Description: "SeaweedFS S3/IAM endpoint, for example https://s3.example.com",
+				Optional:    true,
+				Description: "SeaweedFS S3/IAM endpoint, for example https://s3.example.com. Can also be set with `SEAWEEDFS_ENDPOINT` or `AWS_ENDPOINT_URL`.",
+			},
+			"filer_endpoint": schema.StringAttribute{
+				Optional:    true,
+				Description: "SeaweedFS filer HTTP endpoint, for example http://filer.example.com:8888. Required by resources that manage filer-stored configuration. Can also be set with `SEAWEEDFS_FILER_ENDPOINT`.",
+			},
+			"filer_grpc_endpoint": schema.StringAttribute{
+				Optional:    true,
+				Description: "SeaweedFS filer gRPC address as host:port, for example filer.example.com:18888. Required by attributes stored in filer entries, such as bucket quotas. Default: the host of `filer_endpoint` with its port plus 10000. Uses mutual TLS when `client_cert` is set. Can also be set with `SEAWEEDFS_FILER_GRPC_ENDPOINT`.",
+			},
+			"master_endpoint": schema.StringAttribute{
+				Optional:    true,
+				Description: "SeaweedFS master HTTP endpoint, for example http://master.example.com:9333. Required by resources that manage volumes and collections. Can also be set with `SEAWEEDFS_MASTER_ENDPOINT`.",
 			},
 			"region": schema.StringAttribute{
 				Optional:    true,
-				Description: "Signing region for AWS SigV4. Default: us-east-1.",
+				Description: "Signing region for AWS SigV4. Can also be set with `SEAWEEDFS_REGION` or `AWS_REGION`. Default: us-east-1.",
 			},
 			"access_key": schema.StringAttribute{
-				Required:    true,
+				Optional:    true,
 				Sensitive:   true,
-				Description: "Admin access key used to manage SeaweedFS IAM users.",
+				Description: "Admin access key used to manage SeaweedFS IAM users. Can also be set with `SEAWEEDFS_ACCESS_KEY`, `AWS_ACCESS_KEY_ID` or the shared credentials file.",
 			},
 			"secret_key": schema.StringAttribute{
-				Required:    true,
+				Optional:    true,
 				Sensitive:   true,
-				Description: "Admin secret key used to manage SeaweedFS IAM users.",
+				Description: "Admin secret key used to manage SeaweedFS IAM users. Can also be set with `SEAWEEDFS_SECRET_KEY`, `AWS_SECRET_ACCESS_KEY` or the shared credentials file.",
+			},
+			"shared_credentials_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to an AWS-style shared credentials file, used when no key pair is configured. Can also be set with `SEAWEEDFS_SHARED_CREDENTIALS_FILE` or `AWS_SHARED_CREDENTIALS_FILE`. Default: ~/.aws/credentials.",
+			},
+			"profile": schema.StringAttribute{
+				Optional:    true,
+				Description: "Profile to read from the shared credentials file. Can also be set with `SEAWEEDFS_PROFILE` or `AWS_PROFILE`. Default: default.",
 			},
 			"insecure": schema.BoolAttribute{
 				Optional:    true,
 				Description: "If true, skip TLS certificate verification.",
 			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a PEM file with CA certificates trusted in addition to the system roots.",
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM-encoded CA certificates trusted in addition to the system roots.",
+			},
+			"client_cert": schema.StringAttribute{
+				Optional:    true,
+				Description: "PEM-encoded client certificate for mutual TLS, for example `file(\"client.crt\")`. Requires `client_key`.",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM-encoded private key for `client_cert`.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"assume_role": schema.SingleNestedBlock{
+				Description: "Exchange the configured key pair for short-lived credentials with STS AssumeRole on the SeaweedFS endpoint. The temporary credentials are refreshed automatically before they expire.",
+				Attributes: map[string]schema.Attribute{
+					"role_arn": schema.StringAttribute{
+						Optional:    true,
+						Description: "ARN of the role to assume. Required when the block is set.",
+					},
+					"session_name": schema.StringAttribute{
+						Optional:    true,
+						Description: "Session name recorded for the assumed role. Default: terraform-provider-seaweedfs.",
+					},
+					"duration": schema.StringAttribute{
+						Optional:    true,
+						Description: "Lifetime of the temporary credentials as a Go duration, for example `15m` or `1h`. Default: the server default.",
+					},
+				},
+			},
 		},
 	}
 }
@@ -103,9 +297,62 @@ func (p *seaweedfsProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
-	region := "us-east-1"
-	if !config.Region.IsNull() && !config.Region.IsUnknown() && config.Region.ValueString() != "" {
-		region = config.Region.ValueString()
+	for attribute, value := range map[string]types.String{
+		"endpoint":                config.Endpoint,
+		"filer_endpoint":          config.FilerEndpoint,
+		"filer_grpc_endpoint":     config.FilerGRPCEndpoint,
+		"master_endpoint":         config.MasterEndpoint,
+		"region":                  config.Region,
+		"access_key":              config.AccessKey,
+		"secret_key":              config.SecretKey,
+		"shared_credentials_file": config.SharedCredentialsFile,
+		"profile":                 config.Profile,
+		"ca_cert_file":            config.CACertFile,
+		"ca_cert_pem":             config.CACertPEM,
+		"client_cert":             config.ClientCert,
+		"client_key":              config.ClientKey,
+	} {
+		if value.IsUnknown() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Unknown SeaweedFS provider configuration value",
+				fmt.Sprintf("The provider cannot be configured because %s is not known until apply. Set it statically or use the matching environment variable.", attribute),
+			)
+		}
+	}
+	if config.AssumeRole != nil {
+		for attribute, value := range map[string]types.String{
+			"role_arn":     config.AssumeRole.RoleARN,
+			"session_name": config.AssumeRole.SessionName,
+			"duration":     config.AssumeRole.Duration,
+		} {
+			if value.IsUnknown() {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("assume_role").AtName(attribute),
+					"Unknown SeaweedFS provider configuration value",
+					fmt.Sprintf("The provider cannot be configured because assume_role.%s is not known until apply.", attribute),
+				)
+			}
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	settings, err := resolveProviderSettings(providerSettings{
+		Endpoint:              config.Endpoint.ValueString(),
+		Region:                config.Region.ValueString(),
+		AccessKey:             config.AccessKey.ValueString(),
+		SecretKey:             config.SecretKey.ValueString(),
+		SharedCredentialsFile: config.SharedCredentialsFile.ValueString(),
+		Profile:               config.Profile.ValueString(),
+		FilerEndpoint:         config.FilerEndpoint.ValueString(),
+		FilerGRPCEndpoint:     config.FilerGRPCEndpoint.ValueString(),
+		MasterEndpoint:        config.MasterEndpoint.ValueString(),
+	}, os.Getenv)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to configure SeaweedFS provider", err.Error())
+		return
 	}
 
 	insecure := false
@@ -113,13 +360,48 @@ func (p *seaweedfsProvider) Configure(ctx context.Context, req provider.Configur
 		insecure = config.Insecure.ValueBool()
 	}
 
-	client, err := newIAMClient(iamClientConfig{
-		Endpoint:  config.Endpoint.ValueString(),
-		Region:    region,
-		AccessKey: config.AccessKey.ValueString(),
-		SecretKey: config.SecretKey.ValueString(),
-		Insecure:  insecure,
-	})
+	caCertPEM := config.CACertPEM.ValueString()
+	if file := config.CACertFile.ValueString(); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ca_cert_file"), "Unable to read CA certificate file", err.Error())
+			return
+		}
+		caCertPEM = string(data) + "\n" + caCertPEM
+	}
+
+	var assumeRole *assumeRoleConfig
+	if config.AssumeRole != nil {
+		assumeRole = &assumeRoleConfig{
+			RoleARN:     config.AssumeRole.RoleARN.ValueString(),
+			SessionName: config.AssumeRole.SessionName.ValueString(),
+		}
+		if assumeRole.RoleARN == "" {
+			resp.Diagnostics.AddAttributeError(path.Root("assume_role").AtName("role_arn"), "Missing role ARN", "assume_role requires role_arn.")
+			return
+		}
+		if raw := config.AssumeRole.Duration.ValueString(); raw != "" {
+			duration, err := time.ParseDuration(raw)
+			if err != nil || duration <= 0 {
+				resp.Diagnostics.AddAttributeError(path.Root("assume_role").AtName("duration"), "Invalid duration", fmt.Sprintf("Expected a positive Go duration such as 15m or 1h, got %q.", raw))
+				return
+			}
+			assumeRole.Duration = duration
+		}
+	}
+
+	clientConfig := iamClientConfig{
+		Endpoint:      settings.Endpoint,
+		Region:        settings.Region,
+		AccessKey:     settings.AccessKey,
+		SecretKey:     settings.SecretKey,
+		Insecure:      insecure,
+		CACertPEM:     caCertPEM,
+		ClientCertPEM: config.ClientCert.ValueString(),
+		ClientKeyPEM:  config.ClientKey.ValueString(),
+		AssumeRole:    assumeRole,
+	}
+	client, err := newIAMClient(clientConfig)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to configure SeaweedFS IAM client",
@@ -128,9 +410,66 @@ func (p *seaweedfsProvider) Configure(ctx context.Context, req provider.Configur
 		return
 	}
 
+	// Fail on a bad role or trust policy here rather than in the first resource.
+	if assumeRole != nil {
+		if _, err := client.creds.Retrieve(ctx); err != nil {
+			resp.Diagnostics.AddError("Unable to assume role", iamErrorDetail(err))
+			return
+		}
+	}
+
+	var filer *filerClient
+	if settings.FilerEndpoint != "" {
+		tlsConfig, err := newTLSConfig(clientConfig)
+		if err == nil {
+			filer, err = newFilerClient(settings.FilerEndpoint, tlsConfig)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to configure SeaweedFS filer client", err.Error())
+			return
+		}
+	}
+
+	var filerGRPC *filerGRPCClient
+	filerGRPCAddr := settings.FilerGRPCEndpoint
+	if filerGRPCAddr == "" && settings.FilerEndpoint != "" {
+		// Without an explicit port there is nothing to derive from;
+		// requireFilerGRPC reports it to the resources that need gRPC.
+		filerGRPCAddr, _ = filerGRPCAddress(settings.FilerEndpoint)
+	}
+	if filerGRPCAddr != "" {
+		tlsConfig, err := newTLSConfig(clientConfig)
+		if err == nil {
+			filerGRPC, err = newFilerGRPCClient(filerGRPCAddr, tlsConfig)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to configure SeaweedFS filer gRPC client", err.Error())
+			return
+		}
+	}
+
+	var master *masterClient
+	if settings.MasterEndpoint != "" {
+		tlsConfig, err := newTLSConfig(clientConfig)
+		if err == nil {
+			master, err = newMasterClient(settings.MasterEndpoint, tlsConfig)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to configure SeaweedFS master client", err.Error())
+			return
+		}
+	}
+
 	data := &providerData{
-		client:    client,
-		userLocks: map[string]*sync.Mutex{},
+		client:      client,
+		filer:       filer,
+		filerGRPC:   filerGRPC,
+		master:      master,
+		userLocks:   map[string]*sync.Mutex{},
+		groupLocks:  map[string]*sync.Mutex{},
+		policyLocks: map[string]*sync.Mutex{},
+		roleLocks:   map[string]*sync.Mutex{},
+		filerLocks:  map[string]*sync.Mutex{},
 	}
 	resp.ResourceData = data
 	resp.DataSourceData = data
@@ -139,12 +478,34 @@ func (p *seaweedfsProvider) Configure(ctx context.Context, req provider.Configur
 func (p *seaweedfsProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewBucketResource,
+		NewBucketCORSConfigurationResource,
+		NewBucketLifecycleConfigurationResource,
+		NewBucketPolicyResource,
+		NewS3ObjectResource,
 		NewIAMUserResource,
 		NewIAMAccessKeyResource,
 		NewIAMUserPolicyResource,
+		NewIAMGroupResource,
+		NewIAMGroupMembershipResource,
+		NewIAMGroupPolicyResource,
+		NewIAMPolicyResource,
+		NewIAMUserPolicyAttachmentResource,
+		NewIAMRoleResource,
+		NewIAMRolePolicyResource,
+		NewS3IdentitiesResource,
+		NewFilerDirectoryResource,
+		NewFilerPathConfigResource,
+		NewCollectionResource,
 	}
 }
 
 func (p *seaweedfsProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return nil
+	return []func() datasource.DataSource{
+		NewIAMPolicyDocumentDataSource,
+		NewClusterStatusDataSource,
+		NewBucketDataSource,
+		NewBucketsDataSource,
+		NewIAMUserDataSource,
+		NewIAMUsersDataSource,
+	}
 }
@@ -3,20 +3,25 @@ package seaweedfs
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var (
-	_ resource.Resource                = &bucketResource{}
-	_ resource.ResourceWithConfigure   = &bucketResource{}
-	_ resource.ResourceWithImportState = &bucketResource{}
+	_ resource.Resource                   = &bucketResource{}
+	_ resource.ResourceWithConfigure      = &bucketResource{}
+	_ resource.ResourceWithImportState    = &bucketResource{}
+	_ resource.ResourceWithModifyPlan     = &bucketResource{}
+	_ resource.ResourceWithValidateConfig = &bucketResource{}
 )
 
 func NewBucketResource() resource.Resource {
@@ -25,13 +30,26 @@ func NewBucketResource() resource.Resource {
 
 type bucketResource struct {
 	client *iamClient
+	data   *providerData
 }
 
 type bucketResourceModel struct {
-	ID     types.String `tfsdk:"id"`
-	Bucket types.String `tfsdk:"bucket"`
-	ARN    types.String `tfsdk:"arn"`
-	Tags   types.Map    `tfsdk:"tags"`
+	ID            types.String           `tfsdk:"id"`
+	Bucket        types.String           `tfsdk:"bucket"`
+	ARN           types.String           `tfsdk:"arn"`
+	Tags          types.Map              `tfsdk:"tags"`
+	ForceDestroy  types.Bool             `tfsdk:"force_destroy"`
+	Versioning    *bucketVersioningModel `tfsdk:"versioning"`
+	Replication   types.String           `tfsdk:"replication"`
+	TTL           types.String           `tfsdk:"ttl"`
+	DiskType      types.String           `tfsdk:"disk_type"`
+	Collection    types.String           `tfsdk:"collection"`
+	QuotaBytes    types.Int64            `tfsdk:"quota_bytes"`
+	QuotaEnforced types.Bool             `tfsdk:"quota_enforced"`
+}
+
+type bucketVersioningModel struct {
+	Status types.String `tfsdk:"status"`
 }
 
 func (r *bucketResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -62,6 +80,52 @@ func (r *bucketResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				ElementType: types.StringType,
 				Description: "Bucket tags.",
 			},
+			"force_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "If true, delete all objects, object versions and in-flight multipart uploads before deleting the bucket. Default: false.",
+			},
+			"replication": schema.StringAttribute{
+				Optional:    true,
+				Description: "Replication of new objects, for example `001`. Stored in the bucket's filer path rule; requires `filer_endpoint`. Drift is only detected while set; import takes over the rule's value.",
+			},
+			"ttl": schema.StringAttribute{
+				Optional:    true,
+				Description: "Time to live of new objects, for example `7d`. Stored in the bucket's filer path rule; requires `filer_endpoint`. Drift is only detected while set; import takes over the rule's value.",
+			},
+			"disk_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Disk type of new objects, for example `hdd` or `ssd`. Stored in the bucket's filer path rule; requires `filer_endpoint`. Drift is only detected while set; import takes over the rule's value.",
+			},
+			"collection": schema.StringAttribute{
+				Optional:    true,
+				Description: "Collection new objects are written to. Stored in the bucket's filer path rule; requires `filer_endpoint`. Drift is only detected while set; import takes over the rule's value.",
+			},
+			"quota_bytes": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Size quota of the bucket in bytes, as set by `s3.bucket.quota` in `weed shell`. Stored in the bucket's filer entry; requires the filer gRPC API, see `filer_grpc_endpoint`. Removing it removes the quota.",
+			},
+			"quota_enforced": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Whether quota_bytes is enforced. A disabled quota is kept but not enforced, like `s3.bucket.quota -op=disable`. Default: true.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"versioning": schema.SingleNestedBlock{
+				Description: "Bucket versioning configuration. Once enabled, versioning can only be suspended, not removed.",
+				Attributes: map[string]schema.Attribute{
+					"status": schema.StringAttribute{
+						Required:    true,
+						Description: "Versioning state of the bucket. One of `Enabled` or `Suspended`.",
+						Validators: []validator.String{
+							stringOneOf("Enabled", "Suspended"),
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -76,6 +140,29 @@ func (r *bucketResource) Configure(_ context.Context, req resource.ConfigureRequ
 		return
 	}
 	r.client = data.client
+	r.data = data
+}
+
+func (r *bucketResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config bucketResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Replication.IsNull() && !config.Replication.IsUnknown() {
+		if err := validateReplication(config.Replication.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("replication"), "Invalid replication", err.Error())
+		}
+	}
+	if !config.TTL.IsNull() && !config.TTL.IsUnknown() {
+		if err := validateTTL(config.TTL.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid TTL", err.Error())
+		}
+	}
+	if !config.QuotaBytes.IsNull() && !config.QuotaBytes.IsUnknown() && config.QuotaBytes.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("quota_bytes"), "Invalid quota", "quota_bytes must be at least 1.")
+	}
 }
 
 func (r *bucketResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -97,6 +184,20 @@ func (r *bucketResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 	}
 
+	if plan.hasPlacement() {
+		if err := r.putPlacement(ctx, plan.Bucket.ValueString(), plan.placement(), filerPathConfigSettings{}); err != nil {
+			resp.Diagnostics.AddError("Failed to set bucket storage placement", err.Error())
+			return
+		}
+	}
+
+	if !plan.QuotaBytes.IsNull() {
+		if err := r.putQuota(ctx, plan.Bucket.ValueString(), plan.quota()); err != nil {
+			resp.Diagnostics.AddError("Failed to set bucket quota", err.Error())
+			return
+		}
+	}
+
 	planTags, diags := stringMapFromTerraformMap(ctx, plan.Tags)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -109,6 +210,13 @@ func (r *bucketResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 	}
 
+	if plan.Versioning != nil {
+		if err := r.client.PutBucketVersioning(ctx, plan.Bucket.ValueString(), plan.Versioning.Status.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Failed to set bucket versioning", err.Error())
+			return
+		}
+	}
+
 	remoteTags, err := r.client.GetBucketTags(ctx, plan.Bucket.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read bucket tags", err.Error())
@@ -122,10 +230,18 @@ func (r *bucketResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	state := bucketResourceModel{
-		ID:     types.StringValue(plan.Bucket.ValueString()),
-		Bucket: types.StringValue(plan.Bucket.ValueString()),
-		ARN:    types.StringValue("arn:aws:s3:::" + plan.Bucket.ValueString()),
-		Tags:   tagsValue,
+		ID:            types.StringValue(plan.Bucket.ValueString()),
+		Bucket:        types.StringValue(plan.Bucket.ValueString()),
+		ARN:           types.StringValue("arn:aws:s3:::" + plan.Bucket.ValueString()),
+		Tags:          tagsValue,
+		ForceDestroy:  plan.ForceDestroy,
+		Versioning:    plan.Versioning,
+		Replication:   plan.Replication,
+		TTL:           plan.TTL,
+		DiskType:      plan.DiskType,
+		Collection:    plan.Collection,
+		QuotaBytes:    plan.QuotaBytes,
+		QuotaEnforced: plan.QuotaEnforced,
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -157,9 +273,69 @@ func (r *bucketResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
+	// Versioning is only required when the bucket manages it, so gateways
+	// without versioning support can still refresh other buckets.
+	versioningStatus, err := r.client.GetBucketVersioning(ctx, state.Bucket.ValueString())
+	if err != nil {
+		switch {
+		case state.Versioning != nil:
+			resp.Diagnostics.AddError("Failed to read bucket versioning", err.Error())
+			return
+		case !isNotImplementedError(err):
+			resp.Diagnostics.AddWarning("Failed to read bucket versioning", fmt.Sprintf("Versioning drift of bucket %q is not detected: %s", state.Bucket.ValueString(), err))
+		}
+		versioningStatus = ""
+	}
+
 	state.ID = types.StringValue(state.Bucket.ValueString())
 	state.ARN = types.StringValue("arn:aws:s3:::" + state.Bucket.ValueString())
 	state.Tags = tagsValue
+	state.Versioning = bucketVersioningFromStatus(versioningStatus)
+	// An imported bucket takes over the placement settings of its rule once;
+	// afterwards only the placement attributes in state are refreshed.
+	imported, diags := req.Private.GetKey(ctx, bucketImportedKey)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state.hasPlacement() || (len(imported) > 0 && r.data.filer != nil) {
+		filer, err := r.data.requireFiler("seaweedfs_bucket")
+		if err != nil {
+			resp.Diagnostics.AddError("Filer not configured", err.Error())
+			return
+		}
+		content, err := filer.ReadFile(ctx, filerConfFilePath)
+		if err != nil && !isFilerNotFoundError(err) {
+			resp.Diagnostics.AddError("Failed to read bucket storage placement", err.Error())
+			return
+		}
+		placement, err := readBucketPlacement(content, state.Bucket.ValueString(), state.placement())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read bucket storage placement", err.Error())
+			return
+		}
+		state.setPlacement(placement)
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, bucketImportedKey, nil)...)
+	if !state.QuotaBytes.IsNull() {
+		filerGRPC, err := r.data.requireFilerGRPC("seaweedfs_bucket")
+		if err != nil {
+			resp.Diagnostics.AddError("Filer gRPC not configured", err.Error())
+			return
+		}
+		quota, err := filerGRPC.BucketQuota(ctx, state.Bucket.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to read bucket quota", err.Error())
+			return
+		}
+		state.setQuota(quota)
+	}
+	if state.QuotaEnforced.IsNull() {
+		state.QuotaEnforced = types.BoolValue(true)
+	}
+	if state.ForceDestroy.IsNull() {
+		state.ForceDestroy = types.BoolValue(false)
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -170,6 +346,12 @@ func (r *bucketResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
+	var prior bucketResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	tags, diags := stringMapFromTerraformMap(ctx, plan.Tags)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -188,6 +370,27 @@ func (r *bucketResource) Update(ctx context.Context, req resource.UpdateRequest,
 		}
 	}
 
+	if plan.Versioning != nil && (prior.Versioning == nil || !plan.Versioning.Status.Equal(prior.Versioning.Status)) {
+		if err := r.client.PutBucketVersioning(ctx, plan.Bucket.ValueString(), plan.Versioning.Status.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Failed to update bucket versioning", err.Error())
+			return
+		}
+	}
+
+	if !plan.Replication.Equal(prior.Replication) || !plan.TTL.Equal(prior.TTL) || !plan.DiskType.Equal(prior.DiskType) || !plan.Collection.Equal(prior.Collection) {
+		if err := r.putPlacement(ctx, plan.Bucket.ValueString(), plan.placement(), prior.placement()); err != nil {
+			resp.Diagnostics.AddError("Failed to update bucket storage placement", err.Error())
+			return
+		}
+	}
+
+	if !plan.QuotaBytes.Equal(prior.QuotaBytes) || (!plan.QuotaBytes.IsNull() && !plan.QuotaEnforced.Equal(prior.QuotaEnforced)) {
+		if err := r.putQuota(ctx, plan.Bucket.ValueString(), plan.quota()); err != nil {
+			resp.Diagnostics.AddError("Failed to update bucket quota", err.Error())
+			return
+		}
+	}
+
 	remoteTags, err := r.client.GetBucketTags(ctx, plan.Bucket.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read bucket tags", err.Error())
@@ -200,10 +403,18 @@ func (r *bucketResource) Update(ctx context.Context, req resource.UpdateRequest,
 	}
 
 	state := bucketResourceModel{
-		ID:     types.StringValue(plan.Bucket.ValueString()),
-		Bucket: types.StringValue(plan.Bucket.ValueString()),
-		ARN:    types.StringValue("arn:aws:s3:::" + plan.Bucket.ValueString()),
-		Tags:   tagsValue,
+		ID:            types.StringValue(plan.Bucket.ValueString()),
+		Bucket:        types.StringValue(plan.Bucket.ValueString()),
+		ARN:           types.StringValue("arn:aws:s3:::" + plan.Bucket.ValueString()),
+		Tags:          tagsValue,
+		ForceDestroy:  plan.ForceDestroy,
+		Versioning:    plan.Versioning,
+		Replication:   plan.Replication,
+		TTL:           plan.TTL,
+		DiskType:      plan.DiskType,
+		Collection:    plan.Collection,
+		QuotaBytes:    plan.QuotaBytes,
+		QuotaEnforced: plan.QuotaEnforced,
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -215,14 +426,167 @@ func (r *bucketResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	if state.ForceDestroy.ValueBool() {
+		if err := r.client.EmptyBucket(ctx, state.Bucket.ValueString()); err != nil {
+			if isNoSuchBucketError(err) {
+				return
+			}
+			resp.Diagnostics.AddError("Failed to empty bucket", err.Error())
+			return
+		}
+	}
+
 	if err := r.client.DeleteBucket(ctx, state.Bucket.ValueString()); err != nil && !isNoSuchBucketError(err) {
 		resp.Diagnostics.AddError("Failed to delete bucket", err.Error())
+		return
+	}
+
+	if state.hasPlacement() {
+		// Clear the settings so a bucket recreated under the same name does
+		// not inherit them. The bucket is already gone, so a failure must
+		// not keep it in state.
+		if err := r.putPlacement(ctx, state.Bucket.ValueString(), filerPathConfigSettings{}, state.placement()); err != nil {
+			resp.Diagnostics.AddWarning(
+				"Failed to remove bucket storage placement",
+				fmt.Sprintf("Bucket %q was deleted, but its rule in %s was not cleared and applies to a bucket recreated under the same name: %s", state.Bucket.ValueString(), filerConfFilePath, err),
+			)
+		}
+	}
+}
+
+// ModifyPlan rejects removing the versioning block once versioning has been
+// enabled. S3 semantics only allow moving an enabled bucket to Suspended; it
+// can never return to the "never enabled" state. It also requires the filer
+// clients for placement and quota attributes at plan time, so a missing
+// endpoint cannot fail the apply after the bucket has been created.
+func (r *bucketResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan bucketResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.data != nil {
+		if plan.hasPlacement() {
+			if _, err := r.data.requireFiler("seaweedfs_bucket"); err != nil {
+				resp.Diagnostics.AddError("Filer not configured", err.Error())
+			}
+		}
+		if !plan.QuotaBytes.IsNull() {
+			if _, err := r.data.requireFilerGRPC("seaweedfs_bucket"); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("quota_bytes"), "Filer gRPC not configured", err.Error())
+			}
+		}
+	}
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state bucketResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Versioning != nil && plan.Versioning == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("versioning"),
+			"Bucket versioning cannot be disabled",
+			fmt.Sprintf(
+				"Bucket %q has versioning %s. Once enabled, S3 versioning can only be suspended. Set versioning.status to \"Suspended\" instead of removing the block.",
+				state.Bucket.ValueString(),
+				state.Versioning.Status.ValueString(),
+			),
+		)
 	}
 }
 
 func (r *bucketResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), req.ID)...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, bucketImportedKey, []byte("true"))...)
+}
+
+// bucketImportedKey marks an imported bucket in private state until its
+// first Read.
+const bucketImportedKey = "imported"
+
+// putPlacement writes the storage placement settings of the bucket into its
+// filer path rule and clears those of prior that are no longer configured.
+// Other settings of the rule, for example those managed by
+// seaweedfs_filer_path_config, are kept.
+func (r *bucketResource) putPlacement(ctx context.Context, bucket string, placement filerPathConfigSettings, prior filerPathConfigSettings) error {
+	return r.data.updateFilerFile(ctx, "seaweedfs_bucket", filerConfFilePath, func(current []byte) ([]byte, error) {
+		return editFilerPathConfigSettings(current, bucketLocationPrefix(bucket), placement, prior)
+	})
+}
+
+// putQuota writes quota into the bucket's filer entry.
+func (r *bucketResource) putQuota(ctx context.Context, bucket string, quota int64) error {
+	filerGRPC, err := r.data.requireFilerGRPC("seaweedfs_bucket")
+	if err != nil {
+		return err
+	}
+	return filerGRPC.SetBucketQuota(ctx, bucket, quota)
+}
+
+// quota returns the filer entry quota for the configured attributes:
+// the limit in bytes, negated while not enforced, or zero for none.
+func (m bucketResourceModel) quota() int64 {
+	if m.QuotaBytes.IsNull() {
+		return 0
+	}
+	if !m.QuotaEnforced.IsNull() && !m.QuotaEnforced.ValueBool() {
+		return -m.QuotaBytes.ValueInt64()
+	}
+	return m.QuotaBytes.ValueInt64()
+}
+
+func (m *bucketResourceModel) setQuota(quota int64) {
+	switch {
+	case quota > 0:
+		m.QuotaBytes = types.Int64Value(quota)
+		m.QuotaEnforced = types.BoolValue(true)
+	case quota < 0:
+		m.QuotaBytes = types.Int64Value(-quota)
+		m.QuotaEnforced = types.BoolValue(false)
+	default:
+		m.QuotaBytes = types.Int64Null()
+	}
+}
+
+func (m bucketResourceModel) hasPlacement() bool {
+	return !m.placement().empty()
+}
+
+// placement returns the configured placement settings; null attributes are
+// not managed by the bucket.
+func (m bucketResourceModel) placement() filerPathConfigSettings {
+	return filerPathConfigSettings{
+		Replication: m.Replication.ValueStringPointer(),
+		TTL:         m.TTL.ValueStringPointer(),
+		DiskType:    m.DiskType.ValueStringPointer(),
+		Collection:  m.Collection.ValueStringPointer(),
+	}
+}
+
+func (m *bucketResourceModel) setPlacement(s filerPathConfigSettings) {
+	m.Replication = types.StringPointerValue(s.Replication)
+	m.TTL = types.StringPointerValue(s.TTL)
+	m.DiskType = types.StringPointerValue(s.DiskType)
+	m.Collection = types.StringPointerValue(s.Collection)
+}
+
+func bucketVersioningFromStatus(status string) *bucketVersioningModel {
+	if status == "" {
+		return nil
+	}
+	return &bucketVersioningModel{Status: types.StringValue(status)}
 }
 
 func stringMapFromTerraformMap(ctx context.Context, value types.Map) (map[string]string, diag.Diagnostics) {
@@ -241,3 +605,21 @@ func terraformMapFromStringMap(ctx context.Context, values map[string]string) (t
 	}
 	return types.MapValueFrom(ctx, types.StringType, values)
 }
+
+func stringSliceFromTerraformSet(ctx context.Context, value types.Set) ([]string, diag.Diagnostics) {
+	if value.IsNull() || value.IsUnknown() {
+		return nil, nil
+	}
+
+	var out []string
+	diags := value.ElementsAs(ctx, &out, false)
+	sort.Strings(out)
+	return out, diags
+}
+
+func terraformSetFromStringSlice(ctx context.Context, values []string) (types.Set, diag.Diagnostics) {
+	if len(values) == 0 {
+		return types.SetNull(types.StringType), nil
+	}
+	return types.SetValueFrom(ctx, types.StringType, values)
+}
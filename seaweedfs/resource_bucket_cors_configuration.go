@@ -0,0 +1,310 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &bucketCORSConfigurationResource{}
+	_ resource.ResourceWithConfigure      = &bucketCORSConfigurationResource{}
+	_ resource.ResourceWithImportState    = &bucketCORSConfigurationResource{}
+	_ resource.ResourceWithValidateConfig = &bucketCORSConfigurationResource{}
+)
+
+func NewBucketCORSConfigurationResource() resource.Resource {
+	return &bucketCORSConfigurationResource{}
+}
+
+type bucketCORSConfigurationResource struct {
+	client *iamClient
+}
+
+type bucketCORSConfigurationResourceModel struct {
+	ID       types.String          `tfsdk:"id"`
+	Bucket   types.String          `tfsdk:"bucket"`
+	CORSRule []bucketCORSRuleModel `tfsdk:"cors_rule"`
+}
+
+type bucketCORSRuleModel struct {
+	ID             types.String `tfsdk:"id"`
+	AllowedHeaders types.Set    `tfsdk:"allowed_headers"`
+	AllowedMethods types.Set    `tfsdk:"allowed_methods"`
+	AllowedOrigins types.Set    `tfsdk:"allowed_origins"`
+	ExposeHeaders  types.Set    `tfsdk:"expose_headers"`
+	MaxAgeSeconds  types.Int64  `tfsdk:"max_age_seconds"`
+}
+
+func (r *bucketCORSConfigurationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_cors_configuration"
+}
+
+func (r *bucketCORSConfigurationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the CORS configuration of a SeaweedFS S3 bucket.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Bucket name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"cors_rule": schema.ListNestedBlock{
+				Description: "CORS rule. At least one rule is required.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Optional:    true,
+							Description: "Unique identifier of the rule.",
+						},
+						"allowed_headers": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Headers allowed in preflight requests.",
+						},
+						"allowed_methods": schema.SetAttribute{
+							Required:    true,
+							ElementType: types.StringType,
+							Description: "HTTP methods the origins may execute: `GET`, `PUT`, `HEAD`, `POST` or `DELETE`.",
+						},
+						"allowed_origins": schema.SetAttribute{
+							Required:    true,
+							ElementType: types.StringType,
+							Description: "Origins allowed to access the bucket.",
+						},
+						"expose_headers": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Response headers that browsers may expose to scripts.",
+						},
+						"max_age_seconds": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Time in seconds that browsers may cache the preflight response.",
+							Validators: []validator.Int64{
+								int64Between(0, math.MaxInt32),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *bucketCORSConfigurationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.client = data.client
+}
+
+func (r *bucketCORSConfigurationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var rulesValue types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("cors_rule"), &rulesValue)...)
+	if resp.Diagnostics.HasError() || rulesValue.IsUnknown() {
+		return
+	}
+
+	if len(rulesValue.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("cors_rule"), "Missing CORS rule", "At least one cors_rule block is required.")
+		return
+	}
+
+	var rules []bucketCORSRuleModel
+	resp.Diagnostics.Append(rulesValue.ElementsAs(ctx, &rules, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	allowedMethods := map[string]bool{"GET": true, "PUT": true, "HEAD": true, "POST": true, "DELETE": true}
+	for i, rule := range rules {
+		if rule.AllowedMethods.IsUnknown() {
+			continue
+		}
+		methods, diags := stringSliceFromTerraformSet(ctx, rule.AllowedMethods)
+		resp.Diagnostics.Append(diags...)
+		for _, method := range methods {
+			if !allowedMethods[method] {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("cors_rule").AtListIndex(i).AtName("allowed_methods"),
+					"Invalid CORS method",
+					fmt.Sprintf("Method %q is not supported. Use one of GET, PUT, HEAD, POST or DELETE.", method),
+				)
+			}
+		}
+	}
+}
+
+func (r *bucketCORSConfigurationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan bucketCORSConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, diags := corsRulesFromModel(ctx, plan.CORSRule)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.PutBucketCORSRules(ctx, plan.Bucket.ValueString(), rules); err != nil {
+		resp.Diagnostics.AddError("Failed to create bucket CORS configuration", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Bucket.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *bucketCORSConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state bucketCORSConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, err := r.client.GetBucketCORSRules(ctx, state.Bucket.ValueString())
+	if err != nil {
+		if isNoSuchBucketError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read bucket CORS configuration", err.Error())
+		return
+	}
+	if len(rules) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	models, diags := corsRulesToModel(ctx, rules, state.CORSRule)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ID = types.StringValue(state.Bucket.ValueString())
+	state.CORSRule = models
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *bucketCORSConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan bucketCORSConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, diags := corsRulesFromModel(ctx, plan.CORSRule)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.PutBucketCORSRules(ctx, plan.Bucket.ValueString(), rules); err != nil {
+		resp.Diagnostics.AddError("Failed to update bucket CORS configuration", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Bucket.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *bucketCORSConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state bucketCORSConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteBucketCORS(ctx, state.Bucket.ValueString()); err != nil && !isNoSuchBucketError(err) {
+		resp.Diagnostics.AddError("Failed to delete bucket CORS configuration", err.Error())
+	}
+}
+
+func (r *bucketCORSConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), req.ID)...)
+}
+
+func corsRulesFromModel(ctx context.Context, models []bucketCORSRuleModel) ([]bucketCORSRule, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	rules := make([]bucketCORSRule, 0, len(models))
+	for _, model := range models {
+		allowedHeaders, d := stringSliceFromTerraformSet(ctx, model.AllowedHeaders)
+		diags.Append(d...)
+		allowedMethods, d := stringSliceFromTerraformSet(ctx, model.AllowedMethods)
+		diags.Append(d...)
+		allowedOrigins, d := stringSliceFromTerraformSet(ctx, model.AllowedOrigins)
+		diags.Append(d...)
+		exposeHeaders, d := stringSliceFromTerraformSet(ctx, model.ExposeHeaders)
+		diags.Append(d...)
+
+		rules = append(rules, bucketCORSRule{
+			ID:             model.ID.ValueString(),
+			AllowedHeaders: allowedHeaders,
+			AllowedMethods: allowedMethods,
+			AllowedOrigins: allowedOrigins,
+			ExposeHeaders:  exposeHeaders,
+			MaxAgeSeconds:  int32(model.MaxAgeSeconds.ValueInt64()),
+		})
+	}
+	return rules, diags
+}
+
+// corsRulesToModel converts rules read from the gateway. A max age of zero is
+// not sent on the wire, so a zero in the prior rule at the same position is
+// kept instead of reading back as null.
+func corsRulesToModel(ctx context.Context, rules []bucketCORSRule, prior []bucketCORSRuleModel) ([]bucketCORSRuleModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	models := make([]bucketCORSRuleModel, 0, len(rules))
+	for i, rule := range rules {
+		model := bucketCORSRuleModel{
+			ID:            types.StringNull(),
+			MaxAgeSeconds: int64ValueOrNull(rule.MaxAgeSeconds),
+		}
+		if rule.MaxAgeSeconds == 0 && i < len(prior) && !prior[i].MaxAgeSeconds.IsNull() && prior[i].MaxAgeSeconds.ValueInt64() == 0 {
+			model.MaxAgeSeconds = types.Int64Value(0)
+		}
+		if rule.ID != "" {
+			model.ID = types.StringValue(rule.ID)
+		}
+
+		var d diag.Diagnostics
+		model.AllowedHeaders, d = terraformSetFromStringSlice(ctx, rule.AllowedHeaders)
+		diags.Append(d...)
+		model.AllowedMethods, d = terraformSetFromStringSlice(ctx, rule.AllowedMethods)
+		diags.Append(d...)
+		model.AllowedOrigins, d = terraformSetFromStringSlice(ctx, rule.AllowedOrigins)
+		diags.Append(d...)
+		model.ExposeHeaders, d = terraformSetFromStringSlice(ctx, rule.ExposeHeaders)
+		diags.Append(d...)
+
+		models = append(models, model)
+	}
+	return models, diags
+}
@@ -0,0 +1,363 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &bucketLifecycleConfigurationResource{}
+	_ resource.ResourceWithConfigure      = &bucketLifecycleConfigurationResource{}
+	_ resource.ResourceWithImportState    = &bucketLifecycleConfigurationResource{}
+	_ resource.ResourceWithValidateConfig = &bucketLifecycleConfigurationResource{}
+)
+
+func NewBucketLifecycleConfigurationResource() resource.Resource {
+	return &bucketLifecycleConfigurationResource{}
+}
+
+type bucketLifecycleConfigurationResource struct {
+	client *iamClient
+}
+
+type bucketLifecycleConfigurationResourceModel struct {
+	ID     types.String               `tfsdk:"id"`
+	Bucket types.String               `tfsdk:"bucket"`
+	Rules  []bucketLifecycleRuleModel `tfsdk:"rule"`
+}
+
+type bucketLifecycleRuleModel struct {
+	ID                                 types.String `tfsdk:"id"`
+	Status                             types.String `tfsdk:"status"`
+	Prefix                             types.String `tfsdk:"prefix"`
+	Tags                               types.Map    `tfsdk:"tags"`
+	ExpirationDays                     types.Int64  `tfsdk:"expiration_days"`
+	NoncurrentVersionExpirationDays    types.Int64  `tfsdk:"noncurrent_version_expiration_days"`
+	AbortIncompleteMultipartUploadDays types.Int64  `tfsdk:"abort_incomplete_multipart_upload_days"`
+}
+
+func (r *bucketLifecycleConfigurationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_lifecycle_configuration"
+}
+
+func (r *bucketLifecycleConfigurationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the lifecycle rules of a SeaweedFS S3 bucket. SeaweedFS applies expiration rules through its filer TTL.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Bucket name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"rule": schema.ListNestedBlock{
+				Description: "Lifecycle rule. At least one rule is required.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required:    true,
+							Description: "Unique identifier of the rule.",
+						},
+						"status": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString("Enabled"),
+							Description: "Whether the rule is applied. One of `Enabled` or `Disabled`. Default: `Enabled`.",
+							Validators: []validator.String{
+								stringOneOf("Enabled", "Disabled"),
+							},
+						},
+						"prefix": schema.StringAttribute{
+							Optional:    true,
+							Description: "Object key prefix the rule applies to.",
+						},
+						"tags": schema.MapAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Object tags the rule applies to. All tags must match.",
+						},
+						"expiration_days": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Number of days after creation when objects expire.",
+							Validators: []validator.Int64{
+								int64Between(1, math.MaxInt32),
+							},
+						},
+						"noncurrent_version_expiration_days": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Number of days after becoming noncurrent when object versions expire.",
+							Validators: []validator.Int64{
+								int64Between(1, math.MaxInt32),
+							},
+						},
+						"abort_incomplete_multipart_upload_days": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Number of days after initiation when incomplete multipart uploads are aborted.",
+							Validators: []validator.Int64{
+								int64Between(1, math.MaxInt32),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *bucketLifecycleConfigurationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.client = data.client
+}
+
+func (r *bucketLifecycleConfigurationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var rulesValue types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("rule"), &rulesValue)...)
+	if resp.Diagnostics.HasError() || rulesValue.IsUnknown() {
+		return
+	}
+
+	var rules []bucketLifecycleRuleModel
+	resp.Diagnostics.Append(rulesValue.ElementsAs(ctx, &rules, true)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(rules) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("rule"), "Missing lifecycle rule", "At least one rule block is required.")
+		return
+	}
+
+	seen := map[string]bool{}
+	for i, rule := range rules {
+		rulePath := path.Root("rule").AtListIndex(i)
+
+		if !rule.ID.IsUnknown() && !rule.ID.IsNull() {
+			if seen[rule.ID.ValueString()] {
+				resp.Diagnostics.AddAttributeError(rulePath.AtName("id"), "Duplicate lifecycle rule id", fmt.Sprintf("Rule id %q is used more than once.", rule.ID.ValueString()))
+			}
+			seen[rule.ID.ValueString()] = true
+		}
+
+		hasAction := !rule.ExpirationDays.IsNull() ||
+			!rule.NoncurrentVersionExpirationDays.IsNull() ||
+			!rule.AbortIncompleteMultipartUploadDays.IsNull()
+		if !hasAction {
+			resp.Diagnostics.AddAttributeError(
+				rulePath,
+				"Invalid lifecycle rule",
+				"Each rule must set at least one of expiration_days, noncurrent_version_expiration_days or abort_incomplete_multipart_upload_days.",
+			)
+		}
+	}
+}
+
+func (r *bucketLifecycleConfigurationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan bucketLifecycleConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, diags := lifecycleRulesFromModel(ctx, plan.Rules)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.PutBucketLifecycleRules(ctx, plan.Bucket.ValueString(), rules); err != nil {
+		resp.Diagnostics.AddError("Failed to create bucket lifecycle configuration", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Bucket.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *bucketLifecycleConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state bucketLifecycleConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, err := r.client.GetBucketLifecycleRules(ctx, state.Bucket.ValueString())
+	if err != nil {
+		if isNoSuchBucketError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read bucket lifecycle configuration", err.Error())
+		return
+	}
+	if len(rules) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	order := make([]string, 0, len(state.Rules))
+	for _, rule := range state.Rules {
+		order = append(order, rule.ID.ValueString())
+	}
+	sortLifecycleRules(rules, order)
+
+	models, diags := lifecycleRulesToModel(ctx, rules, state.Rules)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ID = types.StringValue(state.Bucket.ValueString())
+	state.Rules = models
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *bucketLifecycleConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan bucketLifecycleConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	rules, diags := lifecycleRulesFromModel(ctx, plan.Rules)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.PutBucketLifecycleRules(ctx, plan.Bucket.ValueString(), rules); err != nil {
+		resp.Diagnostics.AddError("Failed to update bucket lifecycle configuration", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Bucket.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *bucketLifecycleConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state bucketLifecycleConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteBucketLifecycle(ctx, state.Bucket.ValueString()); err != nil && !isNoSuchBucketError(err) {
+		resp.Diagnostics.AddError("Failed to delete bucket lifecycle configuration", err.Error())
+	}
+}
+
+func (r *bucketLifecycleConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), req.ID)...)
+}
+
+// sortLifecycleRules orders rules to match the rule ids in order, so that a
+// server returning rules in a different order does not produce a diff. Rules
+// not present in order are appended sorted by id.
+func sortLifecycleRules(rules []bucketLifecycleRule, order []string) {
+	position := make(map[string]int, len(order))
+	for i, id := range order {
+		position[id] = i
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		pi, okI := position[rules[i].ID]
+		pj, okJ := position[rules[j].ID]
+		switch {
+		case okI && okJ:
+			return pi < pj
+		case okI != okJ:
+			return okI
+		default:
+			return rules[i].ID < rules[j].ID
+		}
+	})
+}
+
+func lifecycleRulesFromModel(ctx context.Context, models []bucketLifecycleRuleModel) ([]bucketLifecycleRule, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	rules := make([]bucketLifecycleRule, 0, len(models))
+	for _, model := range models {
+		tags, tagDiags := stringMapFromTerraformMap(ctx, model.Tags)
+		diags.Append(tagDiags...)
+
+		rules = append(rules, bucketLifecycleRule{
+			ID:                                 model.ID.ValueString(),
+			Status:                             model.Status.ValueString(),
+			Prefix:                             model.Prefix.ValueString(),
+			Tags:                               tags,
+			ExpirationDays:                     int32(model.ExpirationDays.ValueInt64()),
+			NoncurrentVersionExpirationDays:    int32(model.NoncurrentVersionExpirationDays.ValueInt64()),
+			AbortIncompleteMultipartUploadDays: int32(model.AbortIncompleteMultipartUploadDays.ValueInt64()),
+		})
+	}
+	return rules, diags
+}
+
+// lifecycleRulesToModel converts rules read from the gateway. An empty prefix
+// or tag set is indistinguishable from an unset one on the wire, so the value
+// in prior is kept for rules with the same id to avoid a diff after apply.
+func lifecycleRulesToModel(ctx context.Context, rules []bucketLifecycleRule, prior []bucketLifecycleRuleModel) ([]bucketLifecycleRuleModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	priorByID := make(map[string]bucketLifecycleRuleModel, len(prior))
+	for _, model := range prior {
+		priorByID[model.ID.ValueString()] = model
+	}
+
+	models := make([]bucketLifecycleRuleModel, 0, len(rules))
+	for _, rule := range rules {
+		previous, hasPrevious := priorByID[rule.ID]
+		model := bucketLifecycleRuleModel{
+			ID:                                 types.StringValue(rule.ID),
+			Status:                             types.StringValue(rule.Status),
+			Prefix:                             types.StringNull(),
+			Tags:                               types.MapNull(types.StringType),
+			ExpirationDays:                     int64ValueOrNull(rule.ExpirationDays),
+			NoncurrentVersionExpirationDays:    int64ValueOrNull(rule.NoncurrentVersionExpirationDays),
+			AbortIncompleteMultipartUploadDays: int64ValueOrNull(rule.AbortIncompleteMultipartUploadDays),
+		}
+		if rule.Prefix != "" || (hasPrevious && !previous.Prefix.IsNull()) {
+			model.Prefix = types.StringValue(rule.Prefix)
+		}
+		if len(rule.Tags) > 0 || (hasPrevious && !previous.Tags.IsNull()) {
+			tags, tagDiags := terraformMapFromStringMap(ctx, rule.Tags)
+			diags.Append(tagDiags...)
+			model.Tags = tags
+		}
+		models = append(models, model)
+	}
+	return models, diags
+}
+
+func int64ValueOrNull(value int32) types.Int64 {
+	if value == 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(int64(value))
+}
@@ -0,0 +1,165 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &bucketPolicyResource{}
+	_ resource.ResourceWithConfigure   = &bucketPolicyResource{}
+	_ resource.ResourceWithImportState = &bucketPolicyResource{}
+)
+
+func NewBucketPolicyResource() resource.Resource {
+	return &bucketPolicyResource{}
+}
+
+type bucketPolicyResource struct {
+	client *iamClient
+}
+
+type bucketPolicyResourceModel struct {
+	ID     types.String `tfsdk:"id"`
+	Bucket types.String `tfsdk:"bucket"`
+	Policy types.String `tfsdk:"policy"`
+}
+
+func (r *bucketPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_policy"
+}
+
+func (r *bucketPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the S3 bucket policy of a SeaweedFS bucket.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Bucket name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"policy": schema.StringAttribute{
+				Required:    true,
+				Description: "JSON bucket policy document. Use `\"Principal\": \"*\"` for anonymous access.",
+			},
+		},
+	}
+}
+
+func (r *bucketPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.client = data.client
+}
+
+func (r *bucketPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan bucketPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policyToWrite := plan.Policy.ValueString()
+	if normalized, err := normalizeJSONString(policyToWrite); err == nil {
+		policyToWrite = normalized
+	}
+
+	if err := r.client.PutBucketPolicy(ctx, plan.Bucket.ValueString(), policyToWrite); err != nil {
+		resp.Diagnostics.AddError("Failed to create bucket policy", err.Error())
+		return
+	}
+
+	state := bucketPolicyResourceModel{
+		ID:     types.StringValue(plan.Bucket.ValueString()),
+		Bucket: types.StringValue(plan.Bucket.ValueString()),
+		Policy: types.StringValue(plan.Policy.ValueString()),
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *bucketPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state bucketPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote, err := r.client.GetBucketPolicy(ctx, state.Bucket.ValueString())
+	if err != nil {
+		if isNoSuchBucketError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read bucket policy", err.Error())
+		return
+	}
+	if remote == "" {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Policy = types.StringValue(policyStateFromRemote(state.Policy.ValueString(), remote))
+
+	state.ID = types.StringValue(state.Bucket.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *bucketPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan bucketPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policyToWrite := plan.Policy.ValueString()
+	if normalized, err := normalizeJSONString(policyToWrite); err == nil {
+		policyToWrite = normalized
+	}
+
+	if err := r.client.PutBucketPolicy(ctx, plan.Bucket.ValueString(), policyToWrite); err != nil {
+		resp.Diagnostics.AddError("Failed to update bucket policy", err.Error())
+		return
+	}
+
+	state := bucketPolicyResourceModel{
+		ID:     types.StringValue(plan.Bucket.ValueString()),
+		Bucket: types.StringValue(plan.Bucket.ValueString()),
+		Policy: types.StringValue(plan.Policy.ValueString()),
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *bucketPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state bucketPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteBucketPolicy(ctx, state.Bucket.ValueString()); err != nil && !isNoSuchBucketError(err) && !isNoSuchBucketPolicyError(err) {
+		resp.Diagnostics.AddError("Failed to delete bucket policy", err.Error())
+	}
+}
+
+func (r *bucketPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), req.ID)...)
+}
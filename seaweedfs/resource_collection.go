@@ -0,0 +1,261 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &collectionResource{}
+	_ resource.ResourceWithConfigure      = &collectionResource{}
+	_ resource.ResourceWithImportState    = &collectionResource{}
+	_ resource.ResourceWithValidateConfig = &collectionResource{}
+)
+
+func NewCollectionResource() resource.Resource {
+	return &collectionResource{}
+}
+
+type collectionResource struct {
+	data *providerData
+}
+
+type collectionResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Replication  types.String `tfsdk:"replication"`
+	TTL          types.String `tfsdk:"ttl"`
+	DiskType     types.String `tfsdk:"disk_type"`
+	Count        types.Int64  `tfsdk:"count"`
+	DataCenter   types.String `tfsdk:"data_center"`
+	Rack         types.String `tfsdk:"rack"`
+	ForceDestroy types.Bool   `tfsdk:"force_destroy"`
+}
+
+func (r *collectionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_collection"
+}
+
+func (r *collectionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a SeaweedFS collection by pre-growing its volumes through the master. Only the volumes missing to reach `count` writable volumes with the configured settings are grown; existing volumes are never removed. Requires `master_endpoint`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this resource. Equals name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Collection name. S3 buckets store their objects in the collection with the bucket name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"replication": schema.StringAttribute{
+				Optional:    true,
+				Description: "Replication of the grown volumes, for example `001`. Default: the master's default replication.",
+			},
+			"ttl": schema.StringAttribute{
+				Optional:    true,
+				Description: "Time to live of the grown volumes, for example `7d`.",
+			},
+			"disk_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Disk type of the grown volumes, for example `hdd` or `ssd`.",
+			},
+			"count": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of writable volumes the collection should have with the configured replication, ttl and disk_type. Only missing volumes are grown, so lowering it has no effect. Full and read-only volumes do not count, so once volumes fill up the next apply that changes growth settings grows new ones in their place. Default: the master's default number, grown only while the collection has no such volumes.",
+			},
+			"data_center": schema.StringAttribute{
+				Optional:    true,
+				Description: "Data center to grow missing volumes in. Changing it does not move existing volumes.",
+			},
+			"rack": schema.StringAttribute{
+				Optional:    true,
+				Description: "Rack to grow missing volumes in. Requires data_center. Changing it does not move existing volumes.",
+			},
+			"force_destroy": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "If true, destroying the resource deletes the collection with all its volumes and data. Otherwise destroying only removes it from the Terraform state. Default: false.",
+			},
+		},
+	}
+}
+
+func (r *collectionResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.data = data
+}
+
+func (r *collectionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config collectionResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Replication.IsNull() && !config.Replication.IsUnknown() {
+		if err := validateReplication(config.Replication.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("replication"), "Invalid replication", err.Error())
+		}
+	}
+	if !config.TTL.IsNull() && !config.TTL.IsUnknown() {
+		if err := validateTTL(config.TTL.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid TTL", err.Error())
+		}
+	}
+	if !config.Count.IsNull() && !config.Count.IsUnknown() && config.Count.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("count"), "Invalid volume count", "count must be at least 1.")
+	}
+	if !config.Rack.IsNull() && config.DataCenter.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("rack"), "Missing data center", "rack can only be set together with data_center.")
+	}
+}
+
+func (r *collectionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan collectionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.grow(ctx, plan); err != nil {
+		resp.Diagnostics.AddError("Failed to grow collection volumes", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Name.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *collectionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state collectionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	master, err := r.data.requireMaster("seaweedfs_collection")
+	if err != nil {
+		resp.Diagnostics.AddError("Master not configured", err.Error())
+		return
+	}
+
+	exists, err := master.CollectionExists(ctx, state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read collection", err.Error())
+		return
+	}
+	if !exists {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.ID = types.StringValue(state.Name.ValueString())
+	if state.ForceDestroy.IsNull() {
+		state.ForceDestroy = types.BoolValue(false)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *collectionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan collectionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var prior collectionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// force_destroy only matters on destroy.
+	growthChanged := !plan.Replication.Equal(prior.Replication) ||
+		!plan.TTL.Equal(prior.TTL) ||
+		!plan.DiskType.Equal(prior.DiskType) ||
+		!plan.Count.Equal(prior.Count) ||
+		!plan.DataCenter.Equal(prior.DataCenter) ||
+		!plan.Rack.Equal(prior.Rack)
+	if growthChanged {
+		if err := r.grow(ctx, plan); err != nil {
+			resp.Diagnostics.AddError("Failed to grow collection volumes", err.Error())
+			return
+		}
+	}
+
+	plan.ID = types.StringValue(plan.Name.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *collectionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state collectionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !state.ForceDestroy.ValueBool() {
+		resp.Diagnostics.AddWarning(
+			"Collection kept",
+			fmt.Sprintf("Collection %q and its volumes were removed from the Terraform state but not deleted. Set force_destroy = true to delete the collection with all its data.", state.Name.ValueString()),
+		)
+		return
+	}
+
+	master, err := r.data.requireMaster("seaweedfs_collection")
+	if err != nil {
+		resp.Diagnostics.AddError("Master not configured", err.Error())
+		return
+	}
+
+	if err := master.DeleteCollection(ctx, state.Name.ValueString()); err != nil && !isMasterCollectionNotFoundError(err) {
+		resp.Diagnostics.AddError("Failed to delete collection", err.Error())
+	}
+}
+
+func (r *collectionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_destroy"), false)...)
+}
+
+func (r *collectionResource) grow(ctx context.Context, plan collectionResourceModel) error {
+	master, err := r.data.requireMaster("seaweedfs_collection")
+	if err != nil {
+		return err
+	}
+
+	_, err = master.EnsureVolumes(ctx, masterGrowRequest{
+		Collection:  plan.Name.ValueString(),
+		Replication: plan.Replication.ValueString(),
+		TTL:         plan.TTL.ValueString(),
+		DiskType:    plan.DiskType.ValueString(),
+		Count:       plan.Count.ValueInt64(),
+		DataCenter:  plan.DataCenter.ValueString(),
+		Rack:        plan.Rack.ValueString(),
+	})
+	return err
+}
@@ -0,0 +1,204 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &filerDirectoryResource{}
+	_ resource.ResourceWithConfigure      = &filerDirectoryResource{}
+	_ resource.ResourceWithImportState    = &filerDirectoryResource{}
+	_ resource.ResourceWithValidateConfig = &filerDirectoryResource{}
+)
+
+func NewFilerDirectoryResource() resource.Resource {
+	return &filerDirectoryResource{}
+}
+
+type filerDirectoryResource struct {
+	data *providerData
+}
+
+type filerDirectoryResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Path            types.String `tfsdk:"path"`
+	RecursiveDelete types.Bool   `tfsdk:"recursive_delete"`
+}
+
+func (r *filerDirectoryResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_filer_directory"
+}
+
+func (r *filerDirectoryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a directory in the SeaweedFS filer, for example the root of a FUSE mount or WebDAV share. Requires `filer_endpoint`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this resource. Equals path.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Required:    true,
+				Description: "Absolute directory path, for example `/data/team-a`. Missing parent directories are created.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"recursive_delete": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "If true, destroying the resource deletes the directory with all its content. Otherwise only an empty directory can be destroyed. Default: false.",
+			},
+		},
+	}
+}
+
+func (r *filerDirectoryResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.data = data
+}
+
+func (r *filerDirectoryResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var dirPath types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("path"), &dirPath)...)
+	if resp.Diagnostics.HasError() || dirPath.IsNull() || dirPath.IsUnknown() {
+		return
+	}
+
+	value := dirPath.ValueString()
+	if !strings.HasPrefix(value, "/") || value == "/" || strings.HasSuffix(value, "/") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("path"),
+			"Invalid directory path",
+			fmt.Sprintf("Expected an absolute path below / without a trailing slash, got %q.", value),
+		)
+	}
+}
+
+func (r *filerDirectoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan filerDirectoryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filer, err := r.data.requireFiler("seaweedfs_filer_directory")
+	if err != nil {
+		resp.Diagnostics.AddError("Filer not configured", err.Error())
+		return
+	}
+
+	if err := filer.Mkdir(ctx, plan.Path.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to create filer directory", err.Error())
+		return
+	}
+
+	entry, err := filer.Stat(ctx, plan.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to verify filer directory", err.Error())
+		return
+	}
+	if !entry.IsDirectory() {
+		resp.Diagnostics.AddError("Path is not a directory", fmt.Sprintf("%s already exists as a file.", plan.Path.ValueString()))
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Path.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *filerDirectoryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state filerDirectoryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filer, err := r.data.requireFiler("seaweedfs_filer_directory")
+	if err != nil {
+		resp.Diagnostics.AddError("Filer not configured", err.Error())
+		return
+	}
+
+	entry, err := filer.Stat(ctx, state.Path.ValueString())
+	if err != nil {
+		if isFilerNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read filer directory", err.Error())
+		return
+	}
+	if !entry.IsDirectory() {
+		// A file replaced the directory; plan to recreate it.
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.ID = types.StringValue(state.Path.ValueString())
+	if state.RecursiveDelete.IsNull() {
+		state.RecursiveDelete = types.BoolValue(false)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *filerDirectoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan filerDirectoryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only recursive_delete can change in place; it is used on destroy.
+	plan.ID = types.StringValue(plan.Path.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *filerDirectoryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state filerDirectoryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filer, err := r.data.requireFiler("seaweedfs_filer_directory")
+	if err != nil {
+		resp.Diagnostics.AddError("Filer not configured", err.Error())
+		return
+	}
+
+	if err := filer.Delete(ctx, state.Path.ValueString(), state.RecursiveDelete.ValueBool()); err != nil && !isFilerNotFoundError(err) {
+		detail := err.Error()
+		if !state.RecursiveDelete.ValueBool() {
+			detail += "\n\nSet recursive_delete = true to delete a directory that is not empty."
+		}
+		resp.Diagnostics.AddError("Failed to delete filer directory", detail)
+	}
+}
+
+func (r *filerDirectoryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("path"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("recursive_delete"), false)...)
+}
@@ -0,0 +1,272 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &filerPathConfigResource{}
+	_ resource.ResourceWithConfigure      = &filerPathConfigResource{}
+	_ resource.ResourceWithImportState    = &filerPathConfigResource{}
+	_ resource.ResourceWithValidateConfig = &filerPathConfigResource{}
+)
+
+func NewFilerPathConfigResource() resource.Resource {
+	return &filerPathConfigResource{}
+}
+
+type filerPathConfigResource struct {
+	data *providerData
+}
+
+type filerPathConfigResourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	LocationPrefix    types.String `tfsdk:"location_prefix"`
+	Collection        types.String `tfsdk:"collection"`
+	Replication       types.String `tfsdk:"replication"`
+	TTL               types.String `tfsdk:"ttl"`
+	DiskType          types.String `tfsdk:"disk_type"`
+	Fsync             types.Bool   `tfsdk:"fsync"`
+	VolumeGrowthCount types.Int64  `tfsdk:"volume_growth_count"`
+	ReadOnly          types.Bool   `tfsdk:"read_only"`
+	MaxFileNameLength types.Int64  `tfsdk:"max_file_name_length"`
+}
+
+func (r *filerPathConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_filer_path_config"
+}
+
+func (r *filerPathConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a per-path storage rule in the filer configuration `/etc/seaweedfs/filer.conf`, the rule edited by `weed shell fs.configure`. Only the configured settings are managed, so a `seaweedfs_bucket` can set other settings of the same bucket rule. Requires `filer_endpoint`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this resource. Equals location_prefix.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"location_prefix": schema.StringAttribute{
+				Required:    true,
+				Description: "Path prefix the rule applies to, for example `/buckets/logs/`. Use a trailing slash to avoid matching sibling paths with the same prefix.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"collection": schema.StringAttribute{
+				Optional:    true,
+				Description: "Collection new files are written to.",
+			},
+			"replication": schema.StringAttribute{
+				Optional:    true,
+				Description: "Replication of new files, for example `001`.",
+			},
+			"ttl": schema.StringAttribute{
+				Optional:    true,
+				Description: "Time to live of new files, for example `7d`.",
+			},
+			"disk_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Disk type of new files, for example `hdd` or `ssd`.",
+			},
+			"fsync": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If true, writes are fsynced before they are acknowledged.",
+			},
+			"volume_growth_count": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of volumes to grow at once when the location runs out of writable volumes.",
+			},
+			"read_only": schema.BoolAttribute{
+				Optional:    true,
+				Description: "If true, the location rejects writes.",
+			},
+			"max_file_name_length": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum length of file names below the location.",
+			},
+		},
+	}
+}
+
+func (r *filerPathConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.data = data
+}
+
+func (r *filerPathConfigResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config filerPathConfigResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.LocationPrefix.IsNull() && !config.LocationPrefix.IsUnknown() && !strings.HasPrefix(config.LocationPrefix.ValueString(), "/") {
+		resp.Diagnostics.AddAttributeError(path.Root("location_prefix"), "Invalid location prefix", "location_prefix must be an absolute path.")
+	}
+	if !config.Replication.IsNull() && !config.Replication.IsUnknown() {
+		if err := validateReplication(config.Replication.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("replication"), "Invalid replication", err.Error())
+		}
+	}
+	if !config.TTL.IsNull() && !config.TTL.IsUnknown() {
+		if err := validateTTL(config.TTL.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ttl"), "Invalid TTL", err.Error())
+		}
+	}
+	for attribute, value := range map[string]types.Int64{
+		"volume_growth_count":  config.VolumeGrowthCount,
+		"max_file_name_length": config.MaxFileNameLength,
+	} {
+		if !value.IsNull() && !value.IsUnknown() && value.ValueInt64() < 0 {
+			resp.Diagnostics.AddAttributeError(path.Root(attribute), "Invalid value", fmt.Sprintf("%s must not be negative.", attribute))
+		}
+	}
+	if config.settings().empty() {
+		resp.Diagnostics.AddError("No settings configured", "Configure at least one setting of the filer path rule.")
+	}
+}
+
+func (r *filerPathConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan filerPathConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.put(ctx, plan.LocationPrefix.ValueString(), plan.settings(), filerPathConfigSettings{}); err != nil {
+		resp.Diagnostics.AddError("Failed to write filer path configuration", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(plan.LocationPrefix.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *filerPathConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state filerPathConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filer, err := r.data.requireFiler("seaweedfs_filer_path_config")
+	if err != nil {
+		resp.Diagnostics.AddError("Filer not configured", err.Error())
+		return
+	}
+
+	content, err := filer.ReadFile(ctx, filerConfFilePath)
+	if err != nil && !isFilerNotFoundError(err) {
+		resp.Diagnostics.AddError("Failed to read filer configuration", err.Error())
+		return
+	}
+
+	prefix := state.LocationPrefix.ValueString()
+	cfg, found, err := findFilerPathConfig(content, prefix)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse filer configuration", err.Error())
+		return
+	}
+	// An imported rule has no managed settings yet and takes over all of
+	// them. Otherwise a missing rule reads as one without settings, which
+	// also matches managed settings that are all unset.
+	managed := state.settings()
+	if managed.empty() && !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	settings := filerPathConfigSettingsOf(cfg)
+	if !managed.empty() {
+		settings = managed.refresh(cfg)
+	}
+	state.ID = types.StringValue(prefix)
+	state.setSettings(settings)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *filerPathConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan filerPathConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	var prior filerPathConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.put(ctx, plan.LocationPrefix.ValueString(), plan.settings(), prior.settings()); err != nil {
+		resp.Diagnostics.AddError("Failed to update filer path configuration", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(plan.LocationPrefix.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *filerPathConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state filerPathConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Settings managed elsewhere, for example by a seaweedfs_bucket, stay.
+	if err := r.put(ctx, state.LocationPrefix.ValueString(), filerPathConfigSettings{}, state.settings()); err != nil {
+		resp.Diagnostics.AddError("Failed to delete filer path configuration", err.Error())
+	}
+}
+
+func (r *filerPathConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("location_prefix"), req.ID)...)
+}
+
+// put writes settings into the rule for prefix and clears the settings of
+// prior that are no longer configured. Other settings of the rule are kept.
+func (r *filerPathConfigResource) put(ctx context.Context, prefix string, settings filerPathConfigSettings, prior filerPathConfigSettings) error {
+	return r.data.updateFilerFile(ctx, "seaweedfs_filer_path_config", filerConfFilePath, func(current []byte) ([]byte, error) {
+		return editFilerPathConfigSettings(current, prefix, settings, prior)
+	})
+}
+
+// settings returns the configured settings; null attributes are not managed.
+func (m filerPathConfigResourceModel) settings() filerPathConfigSettings {
+	return filerPathConfigSettings{
+		Collection:        m.Collection.ValueStringPointer(),
+		Replication:       m.Replication.ValueStringPointer(),
+		TTL:               m.TTL.ValueStringPointer(),
+		DiskType:          m.DiskType.ValueStringPointer(),
+		Fsync:             m.Fsync.ValueBoolPointer(),
+		VolumeGrowthCount: m.VolumeGrowthCount.ValueInt64Pointer(),
+		ReadOnly:          m.ReadOnly.ValueBoolPointer(),
+		MaxFileNameLength: m.MaxFileNameLength.ValueInt64Pointer(),
+	}
+}
+
+func (m *filerPathConfigResourceModel) setSettings(s filerPathConfigSettings) {
+	m.Collection = types.StringPointerValue(s.Collection)
+	m.Replication = types.StringPointerValue(s.Replication)
+	m.TTL = types.StringPointerValue(s.TTL)
+	m.DiskType = types.StringPointerValue(s.DiskType)
+	m.Fsync = types.BoolPointerValue(s.Fsync)
+	m.VolumeGrowthCount = types.Int64PointerValue(s.VolumeGrowthCount)
+	m.ReadOnly = types.BoolPointerValue(s.ReadOnly)
+	m.MaxFileNameLength = types.Int64PointerValue(s.MaxFileNameLength)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -44,7 +45,12 @@ func (r *iamAccessKeyResource) Schema(_ context.Context, _ resource.SchemaReques
 	resp.Schema = schema.Schema{
 		Description: "Manages a SeaweedFS IAM access key for a user.",
 		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{Computed: true},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 			"user_name": schema.StringAttribute{
 				Required: true,
 				PlanModifiers: []planmodifier.String{
@@ -53,13 +59,27 @@ func (r *iamAccessKeyResource) Schema(_ context.Context, _ resource.SchemaReques
 			},
 			"access_key_id": schema.StringAttribute{
 				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"secret_access_key": schema.StringAttribute{
 				Computed:  true,
 				Sensitive: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"status": schema.StringAttribute{
-				Computed: true,
+				Optional:    true,
+				Computed:    true,
+				Description: "Key status: `Active` or `Inactive`. Changing it updates the key in place.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringOneOf("Active", "Inactive"),
+				},
 			},
 		},
 	}
@@ -98,12 +118,22 @@ func (r *iamAccessKeyResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
+	status := key.Status
+	if !plan.Status.IsNull() && !plan.Status.IsUnknown() && plan.Status.ValueString() != status {
+		err := r.updateStatus(ctx, plan.UserName.ValueString(), key.AccessKeyID, plan.Status.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to set IAM access key status", err.Error())
+			return
+		}
+		status = plan.Status.ValueString()
+	}
+
 	state := iamAccessKeyResourceModel{
 		ID:              types.StringValue(key.AccessKeyID),
 		UserName:        types.StringValue(plan.UserName.ValueString()),
 		AccessKeyID:     types.StringValue(key.AccessKeyID),
 		SecretAccessKey: types.StringValue(key.SecretAccessKey),
-		Status:          types.StringValue(key.Status),
+		Status:          types.StringValue(status),
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -149,8 +179,25 @@ func (r *iamAccessKeyResource) Read(ctx context.Context, req resource.ReadReques
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
-func (r *iamAccessKeyResource) Update(_ context.Context, _ resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddError("Update not supported", "seaweedfs_iam_access_key supports replacement only.")
+func (r *iamAccessKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan iamAccessKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	var state iamAccessKeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Status.IsUnknown() && !plan.Status.Equal(state.Status) {
+		err := r.updateStatus(ctx, state.UserName.ValueString(), state.AccessKeyID.ValueString(), plan.Status.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to update IAM access key", err.Error())
+			return
+		}
+		state.Status = plan.Status
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 func (r *iamAccessKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -180,3 +227,11 @@ func (r *iamAccessKeyResource) ImportState(ctx context.Context, req resource.Imp
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_name"), parts[0])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("access_key_id"), parts[1])...)
 }
+
+func (r *iamAccessKeyResource) updateStatus(ctx context.Context, userName string, accessKeyID string, status string) error {
+	return r.data.withUserLock(userName, func() error {
+		return retryIAMEventuallyConsistent(ctx, 20, func() error {
+			return r.client.UpdateAccessKey(ctx, userName, accessKeyID, status)
+		})
+	})
+}
@@ -0,0 +1,195 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &iamGroupResource{}
+	_ resource.ResourceWithConfigure   = &iamGroupResource{}
+	_ resource.ResourceWithImportState = &iamGroupResource{}
+)
+
+func NewIAMGroupResource() resource.Resource {
+	return &iamGroupResource{}
+}
+
+type iamGroupResource struct {
+	client *iamClient
+	data   *providerData
+}
+
+type iamGroupResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Path    types.String `tfsdk:"path"`
+	ARN     types.String `tfsdk:"arn"`
+	GroupID types.String `tfsdk:"group_id"`
+}
+
+func (r *iamGroupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iam_group"
+}
+
+func (r *iamGroupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a SeaweedFS IAM group. Requires a SeaweedFS server that implements the IAM group API.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this resource. Equals group name.",
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "IAM group name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("/"),
+				Description: "IAM path for the group.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"arn": schema.StringAttribute{
+				Computed:    true,
+				Description: "ARN returned by SeaweedFS.",
+			},
+			"group_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique group identifier returned by SeaweedFS.",
+			},
+		},
+	}
+}
+
+func (r *iamGroupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.client = data.client
+	r.data = data
+}
+
+func (r *iamGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan iamGroupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.data.withGroupLock(plan.Name.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 8, func() error {
+			_, innerErr := r.client.CreateGroup(ctx, plan.Name.ValueString(), plan.Path.ValueString())
+			return innerErr
+		})
+	})
+	if err != nil && !isEntityAlreadyExistsError(err) {
+		resp.Diagnostics.AddError("Failed to create IAM group", iamErrorDetail(err))
+		return
+	}
+
+	// Adopt an existing group and make sure a new one is visible before
+	// dependent membership or policy resources use it.
+	var group iamGroup
+	err = retryIAMEventuallyConsistent(ctx, 20, func() error {
+		var innerErr error
+		group, _, innerErr = r.client.GetGroup(ctx, plan.Name.ValueString())
+		return innerErr
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to verify IAM group visibility", iamErrorDetail(err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, iamGroupStateFromRemote(plan.Name.ValueString(), group, plan.Path.ValueString()))...)
+}
+
+func (r *iamGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state iamGroupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var group iamGroup
+	err := retryIAMEventuallyConsistent(ctx, 6, func() error {
+		var innerErr error
+		group, _, innerErr = r.client.GetGroup(ctx, state.Name.ValueString())
+		return innerErr
+	})
+	if err != nil {
+		if isNoSuchEntityError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read IAM group", iamErrorDetail(err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, iamGroupStateFromRemote(state.Name.ValueString(), group, state.Path.ValueString()))...)
+}
+
+func (r *iamGroupResource) Update(_ context.Context, _ resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.AddError(
+		"Update not supported",
+		"seaweedfs_iam_group currently supports replacement on changes to name/path only.",
+	)
+}
+
+func (r *iamGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state iamGroupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.data.withGroupLock(state.Name.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 8, func() error {
+			return r.client.DeleteGroup(ctx, state.Name.ValueString())
+		})
+	}); err != nil && !isNoSuchEntityError(err) {
+		resp.Diagnostics.AddError("Failed to delete IAM group", iamErrorDetail(err))
+	}
+}
+
+func (r *iamGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+}
+
+func iamGroupStateFromRemote(name string, group iamGroup, fallbackPath string) *iamGroupResourceModel {
+	groupPath := group.Path
+	if groupPath == "" {
+		groupPath = fallbackPath
+		if groupPath == "" {
+			groupPath = "/"
+		}
+	}
+
+	return &iamGroupResourceModel{
+		ID:      types.StringValue(name),
+		Name:    types.StringValue(name),
+		Path:    types.StringValue(groupPath),
+		ARN:     types.StringValue(group.Arn),
+		GroupID: types.StringValue(group.GroupID),
+	}
+}
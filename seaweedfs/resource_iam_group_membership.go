@@ -0,0 +1,218 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &iamGroupMembershipResource{}
+	_ resource.ResourceWithConfigure   = &iamGroupMembershipResource{}
+	_ resource.ResourceWithImportState = &iamGroupMembershipResource{}
+)
+
+func NewIAMGroupMembershipResource() resource.Resource {
+	return &iamGroupMembershipResource{}
+}
+
+type iamGroupMembershipResource struct {
+	client *iamClient
+	data   *providerData
+}
+
+type iamGroupMembershipResourceModel struct {
+	ID    types.String `tfsdk:"id"`
+	Group types.String `tfsdk:"group"`
+	Users types.Set    `tfsdk:"users"`
+}
+
+func (r *iamGroupMembershipResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iam_group_membership"
+}
+
+func (r *iamGroupMembershipResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the complete member list of a SeaweedFS IAM group. Users added to the group outside Terraform show up as drift.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this resource. Equals group name.",
+			},
+			"group": schema.StringAttribute{
+				Required:    true,
+				Description: "IAM group name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"users": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "IAM user names that are members of the group.",
+			},
+		},
+	}
+}
+
+func (r *iamGroupMembershipResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.client = data.client
+	r.data = data
+}
+
+func (r *iamGroupMembershipResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan iamGroupMembershipResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	users, diags := stringSliceFromTerraformSet(ctx, plan.Users)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.updateMembers(ctx, plan.Group.ValueString(), users, nil); err != nil {
+		resp.Diagnostics.AddError("Failed to create IAM group membership", iamErrorDetail(err))
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Group.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *iamGroupMembershipResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state iamGroupMembershipResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var members []iamUser
+	err := retryIAMEventuallyConsistent(ctx, 6, func() error {
+		var innerErr error
+		_, members, innerErr = r.client.GetGroup(ctx, state.Group.ValueString())
+		return innerErr
+	})
+	if err != nil {
+		if isNoSuchEntityError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read IAM group membership", iamErrorDetail(err))
+		return
+	}
+
+	users := make([]string, 0, len(members))
+	for _, member := range members {
+		users = append(users, member.UserName)
+	}
+	usersValue, diags := types.SetValueFrom(ctx, types.StringType, users)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ID = types.StringValue(state.Group.ValueString())
+	state.Users = usersValue
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *iamGroupMembershipResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan iamGroupMembershipResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	var state iamGroupMembershipResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned, diags := stringSliceFromTerraformSet(ctx, plan.Users)
+	resp.Diagnostics.Append(diags...)
+	current, diags := stringSliceFromTerraformSet(ctx, state.Users)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var add, remove []string
+	for _, user := range planned {
+		if !slices.Contains(current, user) {
+			add = append(add, user)
+		}
+	}
+	for _, user := range current {
+		if !slices.Contains(planned, user) {
+			remove = append(remove, user)
+		}
+	}
+
+	if err := r.updateMembers(ctx, plan.Group.ValueString(), add, remove); err != nil {
+		resp.Diagnostics.AddError("Failed to update IAM group membership", iamErrorDetail(err))
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Group.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *iamGroupMembershipResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state iamGroupMembershipResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	users, diags := stringSliceFromTerraformSet(ctx, state.Users)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.updateMembers(ctx, state.Group.ValueString(), nil, users); err != nil {
+		resp.Diagnostics.AddError("Failed to delete IAM group membership", iamErrorDetail(err))
+	}
+}
+
+func (r *iamGroupMembershipResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group"), req.ID)...)
+}
+
+// updateMembers adds and removes users under the group lock. Removing a user
+// or group that no longer exists is not an error.
+func (r *iamGroupMembershipResource) updateMembers(ctx context.Context, group string, add []string, remove []string) error {
+	return r.data.withGroupLock(group, func() error {
+		for _, user := range add {
+			if err := retryIAMEventuallyConsistent(ctx, 20, func() error {
+				return r.client.AddUserToGroup(ctx, group, user)
+			}); err != nil {
+				return fmt.Errorf("add user %q: %w", user, err)
+			}
+		}
+		for _, user := range remove {
+			if err := retryIAMEventuallyConsistent(ctx, 8, func() error {
+				return r.client.RemoveUserFromGroup(ctx, group, user)
+			}); err != nil && !isNoSuchEntityError(err) {
+				return fmt.Errorf("remove user %q: %w", user, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,201 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource              = &iamGroupPolicyResource{}
+	_ resource.ResourceWithConfigure = &iamGroupPolicyResource{}
+)
+
+func NewIAMGroupPolicyResource() resource.Resource {
+	return &iamGroupPolicyResource{}
+}
+
+type iamGroupPolicyResource struct {
+	client *iamClient
+	data   *providerData
+}
+
+type iamGroupPolicyResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	GroupName types.String `tfsdk:"group_name"`
+	Name      types.String `tfsdk:"name"`
+	Policy    types.String `tfsdk:"policy"`
+
+	IgnorePolicyDrift types.Bool `tfsdk:"ignore_policy_drift"`
+}
+
+func (r *iamGroupPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iam_group_policy"
+}
+
+func (r *iamGroupPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an inline IAM group policy in SeaweedFS.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"group_name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"policy": schema.StringAttribute{
+				Required:    true,
+				Description: "JSON policy document.",
+			},
+			"ignore_policy_drift": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "If true, only check that the policy still exists during refresh and never compare its content. Use this for servers that rewrite stored documents in ways that are not semantically equal. Default: false.",
+			},
+		},
+	}
+}
+
+func (r *iamGroupPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.client = data.client
+	r.data = data
+}
+
+func (r *iamGroupPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan iamGroupPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policyToWrite := plan.Policy.ValueString()
+	if normalized, err := normalizeJSONString(policyToWrite); err == nil {
+		policyToWrite = normalized
+	}
+
+	if err := r.data.withGroupLock(plan.GroupName.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 20, func() error {
+			return r.client.PutGroupPolicy(ctx, plan.GroupName.ValueString(), plan.Name.ValueString(), policyToWrite)
+		})
+	}); err != nil {
+		resp.Diagnostics.AddError("Failed to create IAM group policy", iamErrorDetail(err))
+		return
+	}
+
+	state := iamGroupPolicyResourceModel{
+		ID:        types.StringValue(plan.GroupName.ValueString() + ":" + plan.Name.ValueString()),
+		GroupName: types.StringValue(plan.GroupName.ValueString()),
+		Name:      types.StringValue(plan.Name.ValueString()),
+		Policy:    types.StringValue(plan.Policy.ValueString()),
+
+		IgnorePolicyDrift: plan.IgnorePolicyDrift,
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *iamGroupPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state iamGroupPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var remote string
+	err := retryIAMEventuallyConsistent(ctx, 10, func() error {
+		var innerErr error
+		remote, innerErr = r.client.GetGroupPolicy(ctx, state.GroupName.ValueString(), state.Name.ValueString())
+		return innerErr
+	})
+	if err != nil {
+		if isNoSuchEntityError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read IAM group policy", iamErrorDetail(err))
+		return
+	}
+
+	if state.IgnorePolicyDrift.IsNull() {
+		state.IgnorePolicyDrift = types.BoolValue(false)
+	}
+
+	// Out-of-band permission changes show up in the plan.
+	if !state.IgnorePolicyDrift.ValueBool() && remote != "" {
+		state.Policy = types.StringValue(policyStateFromRemote(state.Policy.ValueString(), remote))
+	}
+
+	state.ID = types.StringValue(state.GroupName.ValueString() + ":" + state.Name.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *iamGroupPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan iamGroupPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policyToWrite := plan.Policy.ValueString()
+	if normalized, err := normalizeJSONString(policyToWrite); err == nil {
+		policyToWrite = normalized
+	}
+
+	if err := r.data.withGroupLock(plan.GroupName.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 20, func() error {
+			return r.client.PutGroupPolicy(ctx, plan.GroupName.ValueString(), plan.Name.ValueString(), policyToWrite)
+		})
+	}); err != nil {
+		resp.Diagnostics.AddError("Failed to update IAM group policy", iamErrorDetail(err))
+		return
+	}
+
+	state := iamGroupPolicyResourceModel{
+		ID:        types.StringValue(plan.GroupName.ValueString() + ":" + plan.Name.ValueString()),
+		GroupName: types.StringValue(plan.GroupName.ValueString()),
+		Name:      types.StringValue(plan.Name.ValueString()),
+		Policy:    types.StringValue(plan.Policy.ValueString()),
+
+		IgnorePolicyDrift: plan.IgnorePolicyDrift,
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *iamGroupPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state iamGroupPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.data.withGroupLock(state.GroupName.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 20, func() error {
+			return r.client.DeleteGroupPolicy(ctx, state.GroupName.ValueString(), state.Name.ValueString())
+		})
+	}); err != nil && !isNoSuchEntityError(err) {
+		resp.Diagnostics.AddError("Failed to delete IAM group policy", iamErrorDetail(err))
+	}
+}
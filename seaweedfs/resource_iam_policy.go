@@ -0,0 +1,284 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &iamPolicyResource{}
+	_ resource.ResourceWithConfigure   = &iamPolicyResource{}
+	_ resource.ResourceWithImportState = &iamPolicyResource{}
+)
+
+func NewIAMPolicyResource() resource.Resource {
+	return &iamPolicyResource{}
+}
+
+type iamPolicyResource struct {
+	client *iamClient
+	data   *providerData
+}
+
+type iamPolicyResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	Path             types.String `tfsdk:"path"`
+	Description      types.String `tfsdk:"description"`
+	Policy           types.String `tfsdk:"policy"`
+	ARN              types.String `tfsdk:"arn"`
+	PolicyID         types.String `tfsdk:"policy_id"`
+	DefaultVersionID types.String `tfsdk:"default_version_id"`
+}
+
+func (r *iamPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iam_policy"
+}
+
+func (r *iamPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a SeaweedFS managed IAM policy. Policy changes create a new default version; the oldest versions are pruned to stay within the 5-version limit.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this resource. Equals the policy ARN.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Policy name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("/"),
+				Description: "IAM path for the policy.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "Policy description.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"policy": schema.StringAttribute{
+				Required:    true,
+				Description: "JSON policy document.",
+			},
+			"arn": schema.StringAttribute{
+				Computed:    true,
+				Description: "ARN returned by SeaweedFS.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"policy_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique policy identifier returned by SeaweedFS.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"default_version_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Identifier of the default policy version.",
+			},
+		},
+	}
+}
+
+func (r *iamPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.client = data.client
+	r.data = data
+}
+
+func (r *iamPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan iamPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policyToWrite := plan.Policy.ValueString()
+	if normalized, err := normalizeJSONString(policyToWrite); err == nil {
+		policyToWrite = normalized
+	}
+
+	var policy iamPolicy
+	// outcomeUnknown records a failed attempt that the server may still have
+	// applied, the only case in which an existing policy can be our own.
+	outcomeUnknown := false
+	err := r.data.withPolicyLock(plan.Name.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 8, func() error {
+			var innerErr error
+			policy, innerErr = r.client.CreatePolicy(ctx, plan.Name.ValueString(), plan.Path.ValueString(), plan.Description.ValueString(), policyToWrite)
+			if isServiceFailureError(innerErr) {
+				outcomeUnknown = true
+			}
+			return innerErr
+		})
+	})
+	if err != nil {
+		if !outcomeUnknown || !isEntityAlreadyExistsError(err) {
+			resp.Diagnostics.AddError("Failed to create IAM policy", iamErrorDetail(err))
+			return
+		}
+		// A retry after a lost response finds the policy already created.
+		// Adopt it only when it holds the planned document; anything else
+		// belongs to someone else and must be imported explicitly.
+		var version iamPolicyVersion
+		readErr := retryIAMEventuallyConsistent(ctx, 6, func() error {
+			var innerErr error
+			policy, innerErr = r.client.GetPolicyByName(ctx, plan.Name.ValueString(), plan.Path.ValueString())
+			if innerErr != nil {
+				return innerErr
+			}
+			version, innerErr = r.client.GetPolicyVersion(ctx, policy.Arn, policy.DefaultVersionID)
+			return innerErr
+		})
+		if readErr != nil {
+			resp.Diagnostics.AddError("Failed to read existing IAM policy", iamErrorDetail(readErr))
+			return
+		}
+		if !policiesSemanticallyEqual(plan.Policy.ValueString(), version.Document) {
+			resp.Diagnostics.AddError(
+				"Failed to create IAM policy",
+				fmt.Sprintf("%s\n\nA policy named %q already exists with a different document. Import it with terraform import to manage it.", iamErrorDetail(err), plan.Name.ValueString()),
+			)
+			return
+		}
+	}
+
+	plan.ID = types.StringValue(policy.Arn)
+	plan.ARN = types.StringValue(policy.Arn)
+	plan.PolicyID = types.StringValue(policy.PolicyID)
+	plan.DefaultVersionID = types.StringValue(firstNonEmpty(policy.DefaultVersionID, "v1"))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *iamPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state iamPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var policy iamPolicy
+	var version iamPolicyVersion
+	err := retryIAMEventuallyConsistent(ctx, 6, func() error {
+		var innerErr error
+		policy, innerErr = r.client.GetPolicy(ctx, state.ARN.ValueString())
+		if innerErr != nil {
+			return innerErr
+		}
+		version, innerErr = r.client.GetPolicyVersion(ctx, state.ARN.ValueString(), policy.DefaultVersionID)
+		return innerErr
+	})
+	if err != nil {
+		if isNoSuchEntityError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read IAM policy", iamErrorDetail(err))
+		return
+	}
+
+	state.Policy = types.StringValue(policyStateFromRemote(state.Policy.ValueString(), version.Document))
+
+	state.ID = types.StringValue(state.ARN.ValueString())
+	if policy.PolicyName != "" {
+		state.Name = types.StringValue(policy.PolicyName)
+	}
+	if policy.Path != "" {
+		state.Path = types.StringValue(policy.Path)
+	} else if state.Path.IsNull() {
+		state.Path = types.StringValue("/")
+	}
+	if policy.Description != "" {
+		state.Description = types.StringValue(policy.Description)
+	}
+	if policy.PolicyID != "" {
+		state.PolicyID = types.StringValue(policy.PolicyID)
+	}
+	state.DefaultVersionID = types.StringValue(policy.DefaultVersionID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *iamPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan iamPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	var state iamPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policyToWrite := plan.Policy.ValueString()
+	if normalized, err := normalizeJSONString(policyToWrite); err == nil {
+		policyToWrite = normalized
+	}
+
+	var version iamPolicyVersion
+	err := r.data.withPolicyLock(plan.Name.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 8, func() error {
+			var innerErr error
+			version, innerErr = r.client.UpdatePolicyDocument(ctx, state.ARN.ValueString(), policyToWrite)
+			return innerErr
+		})
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update IAM policy", iamErrorDetail(err))
+		return
+	}
+
+	plan.ID = state.ID
+	plan.ARN = state.ARN
+	plan.PolicyID = state.PolicyID
+	plan.DefaultVersionID = types.StringValue(version.VersionID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *iamPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state iamPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.data.withPolicyLock(state.Name.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 8, func() error {
+			return r.client.DeletePolicy(ctx, state.ARN.ValueString())
+		})
+	}); err != nil && !isNoSuchEntityError(err) {
+		resp.Diagnostics.AddError("Failed to delete IAM policy", iamErrorDetail(err))
+	}
+}
+
+func (r *iamPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("arn"), req.ID)...)
+}
@@ -0,0 +1,237 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &iamRoleResource{}
+	_ resource.ResourceWithConfigure   = &iamRoleResource{}
+	_ resource.ResourceWithImportState = &iamRoleResource{}
+)
+
+func NewIAMRoleResource() resource.Resource {
+	return &iamRoleResource{}
+}
+
+type iamRoleResource struct {
+	client *iamClient
+	data   *providerData
+}
+
+type iamRoleResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	Path             types.String `tfsdk:"path"`
+	Description      types.String `tfsdk:"description"`
+	AssumeRolePolicy types.String `tfsdk:"assume_role_policy"`
+	ARN              types.String `tfsdk:"arn"`
+	RoleID           types.String `tfsdk:"role_id"`
+}
+
+func (r *iamRoleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iam_role"
+}
+
+func (r *iamRoleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a SeaweedFS IAM role that can be assumed through STS AssumeRole.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this resource. Equals role name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "IAM role name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("/"),
+				Description: "IAM path for the role.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Description: "Role description.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"assume_role_policy": schema.StringAttribute{
+				Required:    true,
+				Description: "JSON trust policy that controls which principals may assume the role.",
+			},
+			"arn": schema.StringAttribute{
+				Computed:    true,
+				Description: "ARN returned by SeaweedFS.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"role_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique role identifier returned by SeaweedFS.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *iamRoleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.client = data.client
+	r.data = data
+}
+
+func (r *iamRoleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan iamRoleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policyToWrite := plan.AssumeRolePolicy.ValueString()
+	if normalized, err := normalizeJSONString(policyToWrite); err == nil {
+		policyToWrite = normalized
+	}
+
+	var role iamRole
+	err := r.data.withRoleLock(plan.Name.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 8, func() error {
+			var innerErr error
+			role, innerErr = r.client.CreateRole(ctx, plan.Name.ValueString(), plan.Path.ValueString(), plan.Description.ValueString(), policyToWrite)
+			return innerErr
+		})
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create IAM role", iamErrorDetail(err))
+		return
+	}
+
+	plan.ID = types.StringValue(plan.Name.ValueString())
+	plan.ARN = types.StringValue(role.Arn)
+	plan.RoleID = types.StringValue(role.RoleID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *iamRoleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state iamRoleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var role iamRole
+	err := retryIAMEventuallyConsistent(ctx, 6, func() error {
+		var innerErr error
+		role, innerErr = r.client.GetRole(ctx, state.Name.ValueString())
+		return innerErr
+	})
+	if err != nil {
+		if isNoSuchEntityError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read IAM role", iamErrorDetail(err))
+		return
+	}
+
+	if role.AssumeRolePolicyDocument != "" {
+		state.AssumeRolePolicy = types.StringValue(policyStateFromRemote(state.AssumeRolePolicy.ValueString(), role.AssumeRolePolicyDocument))
+	}
+
+	state.ID = types.StringValue(state.Name.ValueString())
+	if role.Path != "" {
+		state.Path = types.StringValue(role.Path)
+	} else if state.Path.IsNull() {
+		state.Path = types.StringValue("/")
+	}
+	if role.Description != "" {
+		state.Description = types.StringValue(role.Description)
+	}
+	if role.Arn != "" {
+		state.ARN = types.StringValue(role.Arn)
+	}
+	if role.RoleID != "" {
+		state.RoleID = types.StringValue(role.RoleID)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *iamRoleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan iamRoleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	var state iamRoleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policyToWrite := plan.AssumeRolePolicy.ValueString()
+	if normalized, err := normalizeJSONString(policyToWrite); err == nil {
+		policyToWrite = normalized
+	}
+
+	if err := r.data.withRoleLock(plan.Name.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 20, func() error {
+			return r.client.UpdateAssumeRolePolicy(ctx, plan.Name.ValueString(), policyToWrite)
+		})
+	}); err != nil {
+		resp.Diagnostics.AddError("Failed to update IAM role trust policy", iamErrorDetail(err))
+		return
+	}
+
+	plan.ID = state.ID
+	plan.ARN = state.ARN
+	plan.RoleID = state.RoleID
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *iamRoleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state iamRoleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.data.withRoleLock(state.Name.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 8, func() error {
+			return r.client.DeleteRole(ctx, state.Name.ValueString())
+		})
+	}); err != nil && !isNoSuchEntityError(err) {
+		resp.Diagnostics.AddError("Failed to delete IAM role", iamErrorDetail(err))
+	}
+}
+
+func (r *iamRoleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+}
@@ -4,16 +4,19 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var (
-	_ resource.Resource              = &iamUserPolicyResource{}
-	_ resource.ResourceWithConfigure = &iamUserPolicyResource{}
+	_ resource.Resource                   = &iamUserPolicyResource{}
+	_ resource.ResourceWithConfigure      = &iamUserPolicyResource{}
+	_ resource.ResourceWithValidateConfig = &iamUserPolicyResource{}
 )
 
 func NewIAMUserPolicyResource() resource.Resource {
@@ -30,6 +33,8 @@ type iamUserPolicyResourceModel struct {
 	UserName types.String `tfsdk:"user_name"`
 	Name     types.String `tfsdk:"name"`
 	Policy   types.String `tfsdk:"policy"`
+
+	IgnorePolicyDrift types.Bool `tfsdk:"ignore_policy_drift"`
 }
 
 func (r *iamUserPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -59,6 +64,12 @@ func (r *iamUserPolicyResource) Schema(_ context.Context, _ resource.SchemaReque
 				Required:    true,
 				Description: "JSON policy document.",
 			},
+			"ignore_policy_drift": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "If true, only check that the policy still exists during refresh and never compare its content. Use this for servers that rewrite stored documents in ways that are not semantically equal. Default: false.",
+			},
 		},
 	}
 }
@@ -76,6 +87,25 @@ func (r *iamUserPolicyResource) Configure(_ context.Context, req resource.Config
 	r.data = data
 }
 
+func (r *iamUserPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var policy types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("policy"), &policy)...)
+	if resp.Diagnostics.HasError() || policy.IsNull() || policy.IsUnknown() {
+		return
+	}
+
+	result := validatePolicyDocument(policy.ValueString())
+	for _, problem := range result.Errors {
+		resp.Diagnostics.AddAttributeError(path.Root("policy"), "Invalid IAM policy", problem)
+	}
+	for _, unknown := range result.UnknownActions {
+		resp.Diagnostics.AddAttributeWarning(path.Root("policy"), "IAM policy contains unknown actions", unknown)
+	}
+	for _, warning := range result.Warnings {
+		resp.Diagnostics.AddAttributeWarning(path.Root("policy"), "IAM policy contains actions SeaweedFS ignores", warning)
+	}
+}
+
 func (r *iamUserPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan iamUserPolicyResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -102,6 +132,8 @@ func (r *iamUserPolicyResource) Create(ctx context.Context, req resource.CreateR
 		UserName: types.StringValue(plan.UserName.ValueString()),
 		Name:     types.StringValue(plan.Name.ValueString()),
 		Policy:   types.StringValue(plan.Policy.ValueString()),
+
+		IgnorePolicyDrift: plan.IgnorePolicyDrift,
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -113,8 +145,10 @@ func (r *iamUserPolicyResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
+	var remote string
 	err := retryIAMEventuallyConsistent(ctx, 10, func() error {
-		_, innerErr := r.client.GetUserPolicy(ctx, state.UserName.ValueString(), state.Name.ValueString())
+		var innerErr error
+		remote, innerErr = r.client.GetUserPolicy(ctx, state.UserName.ValueString(), state.Name.ValueString())
 		return innerErr
 	})
 	if err != nil {
@@ -126,6 +160,15 @@ func (r *iamUserPolicyResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
+	if state.IgnorePolicyDrift.IsNull() {
+		state.IgnorePolicyDrift = types.BoolValue(false)
+	}
+
+	// Out-of-band permission changes show up in the plan.
+	if !state.IgnorePolicyDrift.ValueBool() && remote != "" {
+		state.Policy = types.StringValue(policyStateFromRemote(state.Policy.ValueString(), remote))
+	}
+
 	state.ID = types.StringValue(state.UserName.ValueString() + ":" + state.Name.ValueString())
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -156,6 +199,8 @@ func (r *iamUserPolicyResource) Update(ctx context.Context, req resource.UpdateR
 		UserName: types.StringValue(plan.UserName.ValueString()),
 		Name:     types.StringValue(plan.Name.ValueString()),
 		Policy:   types.StringValue(plan.Policy.ValueString()),
+
+		IgnorePolicyDrift: plan.IgnorePolicyDrift,
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
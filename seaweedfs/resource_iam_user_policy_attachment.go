@@ -0,0 +1,168 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = &iamUserPolicyAttachmentResource{}
+	_ resource.ResourceWithConfigure   = &iamUserPolicyAttachmentResource{}
+	_ resource.ResourceWithImportState = &iamUserPolicyAttachmentResource{}
+)
+
+func NewIAMUserPolicyAttachmentResource() resource.Resource {
+	return &iamUserPolicyAttachmentResource{}
+}
+
+type iamUserPolicyAttachmentResource struct {
+	client *iamClient
+	data   *providerData
+}
+
+type iamUserPolicyAttachmentResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	UserName  types.String `tfsdk:"user_name"`
+	PolicyARN types.String `tfsdk:"policy_arn"`
+}
+
+func (r *iamUserPolicyAttachmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iam_user_policy_attachment"
+}
+
+func (r *iamUserPolicyAttachmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Attaches a managed IAM policy to a SeaweedFS IAM user.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this resource. Format: `user_name:policy_arn`.",
+			},
+			"user_name": schema.StringAttribute{
+				Required:    true,
+				Description: "IAM user name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"policy_arn": schema.StringAttribute{
+				Required:    true,
+				Description: "ARN of the managed policy to attach.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *iamUserPolicyAttachmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.client = data.client
+	r.data = data
+}
+
+func (r *iamUserPolicyAttachmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan iamUserPolicyAttachmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.data.withUserLock(plan.UserName.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 20, func() error {
+			return r.client.AttachUserPolicy(ctx, plan.UserName.ValueString(), plan.PolicyARN.ValueString())
+		})
+	}); err != nil {
+		resp.Diagnostics.AddError("Failed to attach IAM user policy", iamErrorDetail(err))
+		return
+	}
+
+	plan.ID = types.StringValue(plan.UserName.ValueString() + ":" + plan.PolicyARN.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *iamUserPolicyAttachmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state iamUserPolicyAttachmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var attached []iamAttachedPolicy
+	err := retryIAMEventuallyConsistent(ctx, 6, func() error {
+		var innerErr error
+		attached, innerErr = r.client.ListAttachedUserPolicies(ctx, state.UserName.ValueString())
+		return innerErr
+	})
+	if err != nil {
+		if isNoSuchEntityError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read IAM user policy attachment", iamErrorDetail(err))
+		return
+	}
+
+	found := false
+	for _, policy := range attached {
+		if policy.PolicyArn == state.PolicyARN.ValueString() {
+			found = true
+			break
+		}
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.ID = types.StringValue(state.UserName.ValueString() + ":" + state.PolicyARN.ValueString())
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *iamUserPolicyAttachmentResource) Update(_ context.Context, _ resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.AddError("Update not supported", "seaweedfs_iam_user_policy_attachment supports replacement only.")
+}
+
+func (r *iamUserPolicyAttachmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state iamUserPolicyAttachmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.data.withUserLock(state.UserName.ValueString(), func() error {
+		return retryIAMEventuallyConsistent(ctx, 8, func() error {
+			return r.client.DetachUserPolicy(ctx, state.UserName.ValueString(), state.PolicyARN.ValueString())
+		})
+	}); err != nil && !isNoSuchEntityError(err) {
+		resp.Diagnostics.AddError("Failed to detach IAM user policy", iamErrorDetail(err))
+	}
+}
+
+func (r *iamUserPolicyAttachmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	userName, policyARN, ok := strings.Cut(req.ID, ":")
+	if !ok || userName == "" || policyARN == "" {
+		resp.Diagnostics.AddError("Invalid import ID", "Expected import id in format `user_name:policy_arn`.")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user_name"), userName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("policy_arn"), policyARN)...)
+}
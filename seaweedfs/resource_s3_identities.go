@@ -0,0 +1,317 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &s3IdentitiesResource{}
+	_ resource.ResourceWithConfigure      = &s3IdentitiesResource{}
+	_ resource.ResourceWithImportState    = &s3IdentitiesResource{}
+	_ resource.ResourceWithValidateConfig = &s3IdentitiesResource{}
+)
+
+func NewS3IdentitiesResource() resource.Resource {
+	return &s3IdentitiesResource{}
+}
+
+type s3IdentitiesResource struct {
+	data *providerData
+}
+
+type s3IdentitiesResourceModel struct {
+	ID         types.String      `tfsdk:"id"`
+	Identities []s3IdentityModel `tfsdk:"identity"`
+}
+
+type s3IdentityModel struct {
+	Name        types.String                `tfsdk:"name"`
+	Actions     types.Set                   `tfsdk:"actions"`
+	Credentials []s3IdentityCredentialModel `tfsdk:"credential"`
+}
+
+type s3IdentityCredentialModel struct {
+	AccessKey types.String `tfsdk:"access_key"`
+	SecretKey types.String `tfsdk:"secret_key"`
+}
+
+func (r *s3IdentitiesResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_identities"
+}
+
+func (r *s3IdentitiesResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the complete list of SeaweedFS S3 identities in `/etc/iam/identity.json` through the filer, the configuration edited by `weed shell s3.configure`. Works on clusters without the IAM API. The IAM API stores its users in the same file, so do not combine this resource with `seaweedfs_iam_*` resources on one cluster: applying it removes IAM-managed users. Requires `filer_endpoint`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Filer path of the identity configuration.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"identity": schema.ListNestedBlock{
+				Description: "S3 identity. Identities present in the file but missing here are removed; other sections of the file, such as accounts, are kept.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required:    true,
+							Description: "Identity name.",
+						},
+						"actions": schema.SetAttribute{
+							Required:    true,
+							ElementType: types.StringType,
+							Description: "Granted actions: `Admin`, `Read`, `Write`, `List` or `Tagging`, optionally scoped to a bucket as `Read:bucket`.",
+						},
+					},
+					Blocks: map[string]schema.Block{
+						"credential": schema.ListNestedBlock{
+							Description: "Access key pair of the identity. Identities without credentials apply to anonymous requests when named `anonymous`.",
+							NestedObject: schema.NestedBlockObject{
+								Attributes: map[string]schema.Attribute{
+									"access_key": schema.StringAttribute{
+										Required:    true,
+										Description: "Access key ID.",
+									},
+									"secret_key": schema.StringAttribute{
+										Required:    true,
+										Sensitive:   true,
+										Description: "Secret access key.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *s3IdentitiesResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.data = data
+}
+
+func (r *s3IdentitiesResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config s3IdentitiesResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	identities := make([]s3Identity, 0, len(config.Identities))
+	for _, model := range config.Identities {
+		identity := s3Identity{Name: model.Name.ValueString()}
+		for _, credential := range model.Credentials {
+			identity.Credentials = append(identity.Credentials, s3IdentityCredential{AccessKey: credential.AccessKey.ValueString()})
+		}
+		identities = append(identities, identity)
+	}
+	for _, problem := range s3IdentityConflicts(identities) {
+		resp.Diagnostics.AddAttributeError(path.Root("identity"), "Duplicate S3 identity", problem)
+	}
+}
+
+func (r *s3IdentitiesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan s3IdentitiesResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	identities, diags := s3IdentitiesFromModel(ctx, plan.Identities)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.write(ctx, func([]s3Identity) []s3Identity { return identities }); err != nil {
+		resp.Diagnostics.AddError("Failed to write S3 identities", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(s3IdentitiesFilePath)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *s3IdentitiesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state s3IdentitiesResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filer, err := r.data.requireFiler("seaweedfs_s3_identities")
+	if err != nil {
+		resp.Diagnostics.AddError("Filer not configured", err.Error())
+		return
+	}
+
+	content, err := filer.ReadFile(ctx, s3IdentitiesFilePath)
+	if err != nil {
+		if isFilerNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read S3 identities", err.Error())
+		return
+	}
+
+	identities, err := parseS3Identities(content)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to parse S3 identities", err.Error())
+		return
+	}
+
+	models, diags := s3IdentityModelsFromRemote(ctx, identities)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.ID = types.StringValue(s3IdentitiesFilePath)
+	state.Identities = models
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *s3IdentitiesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan s3IdentitiesResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	identities, diags := s3IdentitiesFromModel(ctx, plan.Identities)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.write(ctx, func([]s3Identity) []s3Identity { return identities }); err != nil {
+		resp.Diagnostics.AddError("Failed to write S3 identities", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(s3IdentitiesFilePath)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *s3IdentitiesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state s3IdentitiesResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	managed := make([]string, 0, len(state.Identities))
+	for _, identity := range state.Identities {
+		managed = append(managed, identity.Name.ValueString())
+	}
+
+	// Only remove the identities this resource knows about, so identities
+	// added out of band after the last refresh survive.
+	remaining := 0
+	err := r.write(ctx, func(current []s3Identity) []s3Identity {
+		kept := make([]s3Identity, 0, len(current))
+		for _, identity := range current {
+			if !slices.Contains(managed, identity.Name) {
+				kept = append(kept, identity)
+			}
+		}
+		remaining = len(kept)
+		return kept
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to delete S3 identities", err.Error())
+		return
+	}
+	if remaining == 0 {
+		resp.Diagnostics.AddWarning(
+			"No S3 identities left",
+			"SeaweedFS disables S3 authentication when no identities are configured. Make sure the S3 gateways are protected by other means.",
+		)
+	}
+}
+
+func (r *s3IdentitiesResource) ImportState(ctx context.Context, _ resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), s3IdentitiesFilePath)...)
+}
+
+// write applies update to the identities in the filer file, keeping the rest
+// of the document intact.
+func (r *s3IdentitiesResource) write(ctx context.Context, update func(current []s3Identity) []s3Identity) error {
+	return r.data.updateFilerFile(ctx, "seaweedfs_s3_identities", s3IdentitiesFilePath, func(existing []byte) ([]byte, error) {
+		current, err := parseS3Identities(existing)
+		if err != nil {
+			return nil, err
+		}
+
+		updated := update(current)
+		if existing == nil && len(updated) == 0 {
+			return nil, nil
+		}
+		return renderS3Identities(existing, updated)
+	})
+}
+
+func s3IdentitiesFromModel(ctx context.Context, models []s3IdentityModel) ([]s3Identity, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	identities := make([]s3Identity, 0, len(models))
+	for _, model := range models {
+		actions, actionDiags := stringSliceFromTerraformSet(ctx, model.Actions)
+		diags.Append(actionDiags...)
+
+		identity := s3Identity{Name: model.Name.ValueString(), Actions: actions}
+		for _, credential := range model.Credentials {
+			identity.Credentials = append(identity.Credentials, s3IdentityCredential{
+				AccessKey: credential.AccessKey.ValueString(),
+				SecretKey: credential.SecretKey.ValueString(),
+			})
+		}
+		identities = append(identities, identity)
+	}
+	return identities, diags
+}
+
+func s3IdentityModelsFromRemote(ctx context.Context, identities []s3Identity) ([]s3IdentityModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	models := make([]s3IdentityModel, 0, len(identities))
+	for _, identity := range identities {
+		actions, actionDiags := types.SetValueFrom(ctx, types.StringType, identity.Actions)
+		diags.Append(actionDiags...)
+
+		credentials := make([]s3IdentityCredentialModel, 0, len(identity.Credentials))
+		for _, credential := range identity.Credentials {
+			credentials = append(credentials, s3IdentityCredentialModel{
+				AccessKey: types.StringValue(credential.AccessKey),
+				SecretKey: types.StringValue(credential.SecretKey),
+			})
+		}
+		models = append(models, s3IdentityModel{
+			Name:        types.StringValue(identity.Name),
+			Actions:     actions,
+			Credentials: credentials,
+		})
+	}
+	return models, diags
+}
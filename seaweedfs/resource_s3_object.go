@@ -0,0 +1,395 @@
+package seaweedfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &s3ObjectResource{}
+	_ resource.ResourceWithConfigure      = &s3ObjectResource{}
+	_ resource.ResourceWithImportState    = &s3ObjectResource{}
+	_ resource.ResourceWithModifyPlan     = &s3ObjectResource{}
+	_ resource.ResourceWithValidateConfig = &s3ObjectResource{}
+)
+
+func NewS3ObjectResource() resource.Resource {
+	return &s3ObjectResource{}
+}
+
+type s3ObjectResource struct {
+	client *iamClient
+}
+
+type s3ObjectResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Bucket        types.String `tfsdk:"bucket"`
+	Key           types.String `tfsdk:"key"`
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+	Source        types.String `tfsdk:"source"`
+	SourceHash    types.String `tfsdk:"source_hash"`
+	ContentType   types.String `tfsdk:"content_type"`
+	CacheControl  types.String `tfsdk:"cache_control"`
+	Metadata      types.Map    `tfsdk:"metadata"`
+	ETag          types.String `tfsdk:"etag"`
+	VersionID     types.String `tfsdk:"version_id"`
+}
+
+func (r *s3ObjectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_object"
+}
+
+func (r *s3ObjectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an object in a SeaweedFS S3 bucket. Objects larger than 16 MiB are uploaded with multipart upload.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Terraform identifier for this resource. Format: `bucket/key`.",
+			},
+			"bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Bucket name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"key": schema.StringAttribute{
+				Required:    true,
+				Description: "Object key.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"content": schema.StringAttribute{
+				Optional:    true,
+				Description: "Literal UTF-8 object content. Conflicts with `content_base64` and `source`.",
+			},
+			"content_base64": schema.StringAttribute{
+				Optional:    true,
+				Description: "Base64-encoded object content, for binary data. Conflicts with `content` and `source`.",
+			},
+			"source": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a local file to upload. Conflicts with `content` and `content_base64`.",
+			},
+			"source_hash": schema.StringAttribute{
+				Optional:    true,
+				Description: "Arbitrary hash of the `source` file, for example `filemd5(path)`. Changing it triggers an upload.",
+			},
+			"content_type": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "MIME type of the object. Defaults to a type derived from the key extension.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"cache_control": schema.StringAttribute{
+				Optional:    true,
+				Description: "Cache-Control header stored with the object.",
+			},
+			"metadata": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "User metadata stored with the object. Keys must be lowercase.",
+			},
+			"etag": schema.StringAttribute{
+				Computed:    true,
+				Description: "ETag of the object. A local change in content is detected by comparing against this value.",
+			},
+			"version_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Version ID of the object, if the bucket is versioned.",
+			},
+		},
+	}
+}
+
+func (r *s3ObjectResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	data, ok := req.ProviderData.(*providerData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data type", fmt.Sprintf("Expected *providerData, got %T", req.ProviderData))
+		return
+	}
+	r.client = data.client
+}
+
+func (r *s3ObjectResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config s3ObjectResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	set := 0
+	for _, value := range []types.String{config.Content, config.ContentBase64, config.Source} {
+		if !value.IsNull() {
+			set++
+		}
+	}
+	if set > 1 {
+		resp.Diagnostics.AddError(
+			"Conflicting object content",
+			"Only one of content, content_base64 or source can be set.",
+		)
+	}
+
+	if config.Metadata.IsNull() || config.Metadata.IsUnknown() {
+		return
+	}
+	for key := range config.Metadata.Elements() {
+		if key != strings.ToLower(key) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("metadata"),
+				"Invalid metadata key",
+				fmt.Sprintf("Metadata key %q must be lowercase; S3 returns user metadata keys in lowercase.", key),
+			)
+		}
+	}
+}
+
+// ModifyPlan marks etag and version_id as changing when the local content no
+// longer matches the stored etag, so edits to a source file are picked up
+// even if its path is unchanged.
+func (r *s3ObjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state s3ObjectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	var plan s3ObjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	changed := !plan.Content.Equal(state.Content) ||
+		!plan.ContentBase64.Equal(state.ContentBase64) ||
+		!plan.Source.Equal(state.Source) ||
+		!plan.SourceHash.Equal(state.SourceHash) ||
+		!plan.ContentType.Equal(state.ContentType) ||
+		!plan.CacheControl.Equal(state.CacheControl) ||
+		!plan.Metadata.Equal(state.Metadata)
+
+	if !changed {
+		etag, known, err := plannedObjectETag(plan)
+		if err != nil {
+			resp.Diagnostics.AddWarning("Unable to compute object ETag", err.Error())
+		}
+		changed = !known || etag != state.ETag.ValueString()
+	}
+
+	if changed {
+		plan.ETag = types.StringUnknown()
+		plan.VersionID = types.StringUnknown()
+	} else {
+		plan.ETag = state.ETag
+		plan.VersionID = state.VersionID
+	}
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *s3ObjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan s3ObjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.upload(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *s3ObjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state s3ObjectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	info, err := r.client.HeadObject(ctx, state.Bucket.ValueString(), state.Key.ValueString())
+	if err != nil {
+		if isNoSuchKeyError(err) || isNoSuchBucketError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Failed to read S3 object", err.Error())
+		return
+	}
+
+	state.ID = types.StringValue(state.Bucket.ValueString() + "/" + state.Key.ValueString())
+	state.ETag = types.StringValue(info.ETag)
+	state.VersionID = types.StringValue(info.VersionID)
+	state.ContentType = types.StringValue(info.ContentType)
+	state.CacheControl = types.StringNull()
+	if info.CacheControl != "" {
+		state.CacheControl = types.StringValue(info.CacheControl)
+	}
+	if len(info.Metadata) > 0 || !state.Metadata.IsNull() {
+		metadata, diags := terraformMapFromStringMap(ctx, info.Metadata)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.Metadata = metadata
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *s3ObjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan s3ObjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.upload(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *s3ObjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state s3ObjectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteObject(ctx, state.Bucket.ValueString(), state.Key.ValueString()); err != nil && !isNoSuchKeyError(err) && !isNoSuchBucketError(err) {
+		resp.Diagnostics.AddError("Failed to delete S3 object", err.Error())
+	}
+}
+
+func (r *s3ObjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, key, ok := strings.Cut(req.ID, "/")
+	if !ok || bucket == "" || key == "" {
+		resp.Diagnostics.AddError("Invalid import ID", "Expected import id in format `bucket/key`.")
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), key)...)
+}
+
+func (r *s3ObjectResource) upload(ctx context.Context, plan *s3ObjectResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	body, size, closeBody, err := s3ObjectBody(*plan)
+	if err != nil {
+		diags.AddError("Failed to read S3 object content", err.Error())
+		return diags
+	}
+	defer closeBody()
+
+	contentType := plan.ContentType.ValueString()
+	if plan.ContentType.IsNull() || plan.ContentType.IsUnknown() || contentType == "" {
+		contentType = defaultObjectContentType(plan.Key.ValueString())
+	}
+
+	metadata, mapDiags := stringMapFromTerraformMap(ctx, plan.Metadata)
+	diags.Append(mapDiags...)
+	if diags.HasError() {
+		return diags
+	}
+
+	info, err := r.client.PutObject(ctx, s3ObjectInput{
+		Bucket:       plan.Bucket.ValueString(),
+		Key:          plan.Key.ValueString(),
+		ContentType:  contentType,
+		CacheControl: plan.CacheControl.ValueString(),
+		Metadata:     metadata,
+	}, body, size)
+	if err != nil {
+		diags.AddError("Failed to upload S3 object", err.Error())
+		return diags
+	}
+
+	plan.ID = types.StringValue(plan.Bucket.ValueString() + "/" + plan.Key.ValueString())
+	plan.ContentType = types.StringValue(contentType)
+	plan.ETag = types.StringValue(info.ETag)
+	plan.VersionID = types.StringValue(info.VersionID)
+	return diags
+}
+
+// s3ObjectBody opens the configured content source. The returned close
+// function must always be called.
+func s3ObjectBody(model s3ObjectResourceModel) (io.ReaderAt, int64, func() error, error) {
+	noop := func() error { return nil }
+
+	switch {
+	case !model.Source.IsNull():
+		file, err := os.Open(model.Source.ValueString())
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("open source: %w", err)
+		}
+		info, err := file.Stat()
+		if err != nil {
+			_ = file.Close()
+			return nil, 0, nil, fmt.Errorf("stat source: %w", err)
+		}
+		return file, info.Size(), file.Close, nil
+	case !model.ContentBase64.IsNull():
+		data, err := base64.StdEncoding.DecodeString(model.ContentBase64.ValueString())
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("decode content_base64: %w", err)
+		}
+		return bytes.NewReader(data), int64(len(data)), noop, nil
+	default:
+		data := []byte(model.Content.ValueString())
+		return bytes.NewReader(data), int64(len(data)), noop, nil
+	}
+}
+
+// plannedObjectETag computes the ETag the planned content will have once
+// uploaded. known is false when the content is not yet known.
+func plannedObjectETag(plan s3ObjectResourceModel) (string, bool, error) {
+	if plan.Content.IsUnknown() || plan.ContentBase64.IsUnknown() || plan.Source.IsUnknown() {
+		return "", false, nil
+	}
+
+	body, size, closeBody, err := s3ObjectBody(plan)
+	if err != nil {
+		return "", false, err
+	}
+	defer closeBody()
+
+	etag, err := computeObjectETag(body, size)
+	if err != nil {
+		return "", false, err
+	}
+	return etag, true, nil
+}
+
+func defaultObjectContentType(key string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(key)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,170 @@
+package seaweedfs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// s3IdentitiesFilePath is where SeaweedFS S3 gateways load identities from
+// when they run with the filer-backed IAM configuration.
+const s3IdentitiesFilePath = "/etc/iam/identity.json"
+
+type s3Identity struct {
+	Name        string
+	Actions     []string
+	Credentials []s3IdentityCredential
+}
+
+type s3IdentityCredential struct {
+	AccessKey string
+	SecretKey string
+}
+
+// parseS3Identities reads the identities from an identity.json document as
+// written by `weed shell s3.configure`.
+func parseS3Identities(data []byte) ([]s3Identity, error) {
+	document, err := parseS3IdentitiesDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, _ := document["identities"].([]any)
+	identities := make([]s3Identity, 0, len(entries))
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid identity entry: %v", entry)
+		}
+
+		identity := s3Identity{Actions: jsonStringList(fields["actions"])}
+		identity.Name, _ = fields["name"].(string)
+		credentials, _ := fields["credentials"].([]any)
+		for _, credential := range credentials {
+			values, ok := credential.(map[string]any)
+			if !ok {
+				continue
+			}
+			identity.Credentials = append(identity.Credentials, s3IdentityCredential{
+				AccessKey: jsonStringField(values, "accessKey", "access_key"),
+				SecretKey: jsonStringField(values, "secretKey", "secret_key"),
+			})
+		}
+		identities = append(identities, identity)
+	}
+	return identities, nil
+}
+
+// renderS3Identities replaces the identities in an existing identity.json
+// document. Other top-level sections, such as accounts, and fields of an
+// identity that this provider does not manage are kept.
+func renderS3Identities(existing []byte, identities []s3Identity) ([]byte, error) {
+	document, err := parseS3IdentitiesDocument(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := map[string]map[string]any{}
+	entries, _ := document["identities"].([]any)
+	for _, entry := range entries {
+		if fields, ok := entry.(map[string]any); ok {
+			if name, _ := fields["name"].(string); name != "" {
+				previous[name] = fields
+			}
+		}
+	}
+
+	rendered := make([]any, 0, len(identities))
+	for _, identity := range identities {
+		fields := previous[identity.Name]
+		if fields == nil {
+			fields = map[string]any{}
+		}
+
+		credentials := make([]any, 0, len(identity.Credentials))
+		for _, credential := range identity.Credentials {
+			credentials = append(credentials, map[string]any{
+				"accessKey": credential.AccessKey,
+				"secretKey": credential.SecretKey,
+			})
+		}
+		actions := make([]any, 0, len(identity.Actions))
+		for _, action := range identity.Actions {
+			actions = append(actions, action)
+		}
+
+		fields["name"] = identity.Name
+		fields["credentials"] = credentials
+		fields["actions"] = actions
+		rendered = append(rendered, fields)
+	}
+	document["identities"] = rendered
+
+	out, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// s3IdentityConflicts returns a problem for every identity name and access
+// key used more than once. renderS3Identities keys identities by name and the
+// S3 gateway looks them up by access key, so both must be unique. Empty
+// values, such as values unknown at plan time, are skipped.
+func s3IdentityConflicts(identities []s3Identity) []string {
+	var problems []string
+	names := map[string]bool{}
+	accessKeys := map[string]string{}
+	for _, identity := range identities {
+		if identity.Name != "" {
+			if names[identity.Name] {
+				problems = append(problems, fmt.Sprintf("identity name %q is used more than once", identity.Name))
+			}
+			names[identity.Name] = true
+		}
+		for _, credential := range identity.Credentials {
+			if credential.AccessKey == "" {
+				continue
+			}
+			if owner, ok := accessKeys[credential.AccessKey]; ok {
+				problems = append(problems, fmt.Sprintf("access key %q of identity %q is already used by identity %q", credential.AccessKey, identity.Name, owner))
+				continue
+			}
+			accessKeys[credential.AccessKey] = identity.Name
+		}
+	}
+	return problems
+}
+
+func parseS3IdentitiesDocument(data []byte) (map[string]any, error) {
+	document := map[string]any{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return document, nil
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid identity configuration: %w", err)
+	}
+	return document, nil
+}
+
+func jsonStringList(value any) []string {
+	items, _ := value.([]any)
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if text, ok := item.(string); ok {
+			out = append(out, text)
+		}
+	}
+	return out
+}
+
+// jsonStringField returns the first key present in fields. The identity file
+// is protobuf JSON, which accepts both camelCase and snake_case names.
+func jsonStringField(fields map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := fields[key].(string); ok {
+			return value
+		}
+	}
+	return ""
+}
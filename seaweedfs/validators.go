@@ -0,0 +1,115 @@
+package seaweedfs
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var (
+	_ validator.String = stringOneOfValidator{}
+	_ validator.String = stringRegexValidator{}
+	_ validator.Int64  = int64BetweenValidator{}
+)
+
+type stringOneOfValidator struct {
+	values []string
+}
+
+func stringOneOf(values ...string) validator.String {
+	return stringOneOfValidator{values: values}
+}
+
+func (v stringOneOfValidator) Description(_ context.Context) string {
+	quoted := make([]string, 0, len(v.values))
+	for _, value := range v.values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return "value must be one of: " + strings.Join(quoted, ", ")
+}
+
+func (v stringOneOfValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringOneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if slices.Contains(v.values, value) {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid attribute value",
+		fmt.Sprintf("Attribute %s %s, got: %q", req.Path, v.Description(ctx), value),
+	)
+}
+
+type stringRegexValidator struct{}
+
+func stringRegex() validator.String {
+	return stringRegexValidator{}
+}
+
+func (v stringRegexValidator) Description(_ context.Context) string {
+	return "value must be a valid RE2 regular expression"
+}
+
+func (v stringRegexValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v stringRegexValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := regexp.Compile(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid regular expression",
+			fmt.Sprintf("Attribute %s is not a valid regular expression: %s", req.Path, err),
+		)
+	}
+}
+
+type int64BetweenValidator struct {
+	min, max int64
+}
+
+func int64Between(min, max int64) validator.Int64 {
+	return int64BetweenValidator{min: min, max: max}
+}
+
+func (v int64BetweenValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be between %d and %d", v.min, v.max)
+}
+
+func (v int64BetweenValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v int64BetweenValidator) ValidateInt64(ctx context.Context, req validator.Int64Request, resp *validator.Int64Response) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueInt64()
+	if value >= v.min && value <= v.max {
+		return
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		req.Path,
+		"Invalid attribute value",
+		fmt.Sprintf("Attribute %s %s, got: %d", req.Path, v.Description(ctx), value),
+	)
+}